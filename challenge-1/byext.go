@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+// noExtension names the group of files without an extension.
+const noExtension = "(none)"
+
+// extensionReporter groups the rows by file extension for --by-ext, and
+// writes one row per extension, most lines first, when the total comes. The
+// groups start again after each total, for the rounds of --follow. Distinct
+// items are left blank, since they do not add up.
+type extensionReporter struct {
+	out    reporter
+	groups map[string]*wc.Counts
+}
+
+func newExtensionReporter(out reporter) *extensionReporter {
+	return &extensionReporter{out: out, groups: map[string]*wc.Counts{}}
+}
+
+func (e *extensionReporter) report(result wc.Counts, name string) error {
+	base := filepath.Base(name)
+	ext := filepath.Ext(base)
+	if ext == "" || ext == base {
+		// Dot files such as .gitignore have no extension.
+		ext = noExtension
+	}
+	group, ok := e.groups[ext]
+	if !ok {
+		group = &wc.Counts{}
+		e.groups[ext] = group
+	}
+	group.Add(result)
+	return nil
+}
+
+func (e *extensionReporter) total(result wc.Counts, inputs int) error {
+	exts := make([]string, 0, len(e.groups))
+	for ext := range e.groups {
+		exts = append(exts, ext)
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		a, b := e.groups[exts[i]], e.groups[exts[j]]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return exts[i] < exts[j]
+	})
+
+	groups := e.groups
+	e.groups = map[string]*wc.Counts{}
+	for _, ext := range exts {
+		if err := e.out.report(withoutDistinct(*groups[ext]), ext); err != nil {
+			return err
+		}
+	}
+	return e.out.total(result, len(exts))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+func Test_extensionReporter(t *testing.T) {
+	var out strings.Builder
+	opts := options{lines: true, words: true}
+	table := &tableReporter{w: &out, columns: selectedColumns(opts), width: 1}
+	byExt := newExtensionReporter(table)
+
+	rows := map[string]wc.Counts{
+		"main.go":           {Lines: 10, Words: 20},
+		"src/parser.go":     {Lines: 5, Words: 5},
+		"README.md":         {Lines: 30, Words: 100},
+		"Makefile":          {Lines: 2, Words: 4},
+		"src/.gitignore":    {Lines: 1, Words: 1},
+		"docs/notes.v2.txt": {Lines: 15, Words: 1},
+	}
+	var total wc.Counts
+	for name, result := range rows {
+		byExt.report(result, name)
+		total.Add(result)
+	}
+	byExt.total(total, len(rows))
+
+	expected := "" +
+		"30 100 .md\n" +
+		"15 25 .go\n" +
+		"15 1 .txt\n" +
+		"3 5 (none)\n" +
+		"63 131 total\n"
+	if out.String() != expected {
+		t.Error("Expected:", expected, "Actual", out.String())
+	}
+}
+
+func Test_extensionReporterRounds(t *testing.T) {
+	var out strings.Builder
+	opts := options{lines: true, distinct: newDistinctCounter(false)}
+	table := &tableReporter{w: &out, columns: selectedColumns(opts), width: 1}
+	byExt := newExtensionReporter(table)
+
+	for _, lines := range []int{2, 5} {
+		result := wc.Counts{Lines: lines, DistinctLines: 1, DistinctWords: 1}
+		byExt.report(result, "a.go")
+		byExt.report(result, "b.md")
+		total := wc.Counts{Lines: 2 * lines, DistinctLines: 1, DistinctWords: 1}
+		byExt.total(total, 2)
+	}
+
+	expected := "" +
+		"2     .go\n" +
+		"2     .md\n" +
+		"4 1 1 total\n" +
+		"5     .go\n" +
+		"5     .md\n" +
+		"10 1 1 total\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%q\nActual\n%q", expected, out.String())
+	}
+}
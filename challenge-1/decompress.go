@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+const (
+	compressionGzip  = "gzip"
+	compressionBzip2 = "bzip2"
+	compressionZstd  = "zstd"
+	compressionXz    = "xz"
+)
+
+// magicSize is how many bytes are needed to tell the formats apart.
+const magicSize = 10
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+
+	// A bzip2 stream starts with a block, or ends at once when empty.
+	bzip2Block = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2End   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// compressionOf names the compression format whose magic bytes start data,
+// or returns "" for anything else.
+func compressionOf(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		return compressionGzip
+	case bytes.HasPrefix(data, zstdMagic):
+		return compressionZstd
+	case bytes.HasPrefix(data, xzMagic):
+		return compressionXz
+	case len(data) >= magicSize && bytes.HasPrefix(data, bzip2Magic) && '1' <= data[3] && data[3] <= '9':
+		block := data[4:magicSize]
+		if bytes.Equal(block, bzip2Block) || bytes.Equal(block, bzip2End) {
+			return compressionBzip2
+		}
+	}
+	return ""
+}
+
+// decompress returns the decompressed data of gzip and bzip2 input, and any
+// other input as is. The standard library cannot read zstd or xz, so those
+// are reported instead of being counted as if they were text.
+func decompress(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(reader)
+	magic, _ := buffered.Peek(magicSize)
+	switch compression := compressionOf(magic); compression {
+	case compressionGzip:
+		return gzip.NewReader(buffered)
+	case compressionBzip2:
+		return bzip2.NewReader(buffered), nil
+	case compressionZstd, compressionXz:
+		return nil, fmt.Errorf("%s compressed input is not supported, decompress it first or count it with --raw", compression)
+	}
+	return buffered, nil
+}
+
+// isCompressed reports whether the input starts with the magic bytes of a
+// compression format.
+func isCompressed(reader io.ReaderAt) bool {
+	magic := make([]byte, magicSize)
+	n, _ := reader.ReadAt(magic, 0)
+	return compressionOf(magic[:n]) != ""
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_compressionOf(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"Should detect gzip":               {input: "\x1f\x8b\x08\x00", expected: compressionGzip},
+		"Should detect bzip2":              {input: "BZh91AY&SY\x00", expected: compressionBzip2},
+		"Should detect empty bzip2":        {input: "BZh9\x17\x72\x45\x38\x50\x90", expected: compressionBzip2},
+		"Should detect zstd":               {input: "\x28\xb5\x2f\xfd\x00", expected: compressionZstd},
+		"Should detect xz":                 {input: "\xfd7zXZ\x00\x00", expected: compressionXz},
+		"Should not take text for bzip2":   {input: "BZh9 is not a block", expected: ""},
+		"Should not take text for gzip":    {input: "plain text", expected: ""},
+		"Should not detect a short prefix": {input: "\x1f", expected: ""},
+	}
+
+	for k, v := range testcases {
+		if actual := compressionOf([]byte(v.input)); actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_countFileCompressed(t *testing.T) {
+	testcases := map[string]struct {
+		filePath string
+		opts     options
+		bytes    int
+	}{
+		"Should decompress gzip":      {filePath: "testdata/file.txt.gz", bytes: 3568},
+		"Should decompress bzip2":     {filePath: "testdata/file.txt.bz2", bytes: 3568},
+		"Should count gzip as is":     {filePath: "testdata/file.txt.gz", opts: options{raw: true}, bytes: 1840},
+		"Should count bzip2 as is":    {filePath: "testdata/file.txt.bz2", opts: options{raw: true, parallel: true}, bytes: 1733},
+		"Should decompress in chunks": {filePath: "testdata/file.txt.gz", opts: options{parallel: true}, bytes: 3568},
+	}
+
+	for k, v := range testcases {
+		actual, err := countFile(v.filePath, v.opts)
+		if err != nil {
+			t.Error(k, err)
+		}
+		if actual.Bytes != v.bytes {
+			t.Error(k, "Expected:", v.bytes, "Actual", actual.Bytes)
+		}
+	}
+}
+
+func Test_countStreamUnsupported(t *testing.T) {
+	_, err := countStream(strings.NewReader("\x28\xb5\x2f\xfd\x00\x00"), options{})
+	if err == nil || !strings.Contains(err.Error(), "zstd") {
+		t.Error("Expecting zstd error, Actual", err)
+	}
+}
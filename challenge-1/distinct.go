@@ -0,0 +1,150 @@
+package main
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+)
+
+// hyperLogLogPrecision makes 2^14 registers, 16KB, for a standard error of
+// 1.04/sqrt(2^14), about 0.8%.
+const hyperLogLogPrecision = 14
+
+// distinctSet counts the distinct items added to it.
+type distinctSet interface {
+	add(item []byte)
+	count() int
+	// merge adds the items of other, a set of the same kind.
+	merge(other distinctSet)
+}
+
+// distinctCounter counts the distinct lines and words of the data written to
+// it for --distinct. Each input gets its own sets, which are merged into the
+// sets of the total when it is kept, since distinct counts do not add up.
+type distinctCounter struct {
+	approx bool
+	seed   maphash.Seed
+
+	lines      distinctSet
+	words      distinctSet
+	lineItems  *itemWriter
+	wordItems  *itemWriter
+	totalLines distinctSet
+	totalWords distinctSet
+}
+
+func newDistinctCounter(approx bool) *distinctCounter {
+	d := &distinctCounter{approx: approx, seed: maphash.MakeSeed()}
+	d.totalLines, d.totalWords = d.newSet(), d.newSet()
+	d.reset()
+	return d
+}
+
+func (d *distinctCounter) newSet() distinctSet {
+	if d.approx {
+		return newHyperLogLog(d.seed)
+	}
+	return exactSet{}
+}
+
+func (d *distinctCounter) reset() {
+	d.lines, d.words = d.newSet(), d.newSet()
+	d.lineItems, _ = newItemWriter(byLine, d.lines.add)
+	d.wordItems, _ = newItemWriter(byWord, d.words.add)
+}
+
+func (d *distinctCounter) Write(p []byte) (int, error) {
+	d.lineItems.Write(p)
+	d.wordItems.Write(p)
+	return len(p), nil
+}
+
+// finish ends the current input and returns its distinct lines and words.
+// They go into the totals once the input is kept.
+func (d *distinctCounter) finish() (lines, words int) {
+	d.lineItems.Flush()
+	d.wordItems.Flush()
+	return d.lines.count(), d.words.count()
+}
+
+// keep adds the items of the current input to the totals, or throws them
+// away when the input failed, as its other counts are left out of the total.
+// The next input starts with empty sets either way.
+func (d *distinctCounter) keep(ok bool) {
+	if d == nil {
+		return
+	}
+	if ok {
+		d.lineItems.Flush()
+		d.wordItems.Flush()
+		d.totalLines.merge(d.lines)
+		d.totalWords.merge(d.words)
+	}
+	d.reset()
+}
+
+// totals returns the distinct lines and words of all the inputs together.
+func (d *distinctCounter) totals() (lines, words int) {
+	return d.totalLines.count(), d.totalWords.count()
+}
+
+// exactSet keeps every distinct item in memory.
+type exactSet map[string]struct{}
+
+func (e exactSet) add(item []byte) {
+	e[string(item)] = struct{}{}
+}
+
+func (e exactSet) count() int {
+	return len(e)
+}
+
+func (e exactSet) merge(other distinctSet) {
+	for item := range other.(exactSet) {
+		e[item] = struct{}{}
+	}
+}
+
+// hyperLogLog estimates the number of distinct items in constant memory.
+// Sets merge when they share a seed.
+type hyperLogLog struct {
+	seed      maphash.Seed
+	registers []uint8
+}
+
+func newHyperLogLog(seed maphash.Seed) *hyperLogLog {
+	return &hyperLogLog{seed: seed, registers: make([]uint8, 1<<hyperLogLogPrecision)}
+}
+
+func (h *hyperLogLog) add(item []byte) {
+	hash := maphash.Bytes(h.seed, item)
+	index := hash >> (64 - hyperLogLogPrecision)
+	// The rank is the position of the first 1 bit after the index bits,
+	// capped in case every one of them is 0.
+	rest := hash<<hyperLogLogPrecision | 1<<(hyperLogLogPrecision-1)
+	rank := uint8(bits.LeadingZeros64(rest) + 1)
+	h.registers[index] = max(h.registers[index], rank)
+}
+
+func (h *hyperLogLog) count() int {
+	m := float64(len(h.registers))
+	sum, zeros := 0.0, 0
+	for _, register := range h.registers {
+		sum += math.Ldexp(1, -int(register))
+		if register == 0 {
+			zeros++
+		}
+	}
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for small sets.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(estimate))
+}
+
+func (h *hyperLogLog) merge(other distinctSet) {
+	for i, register := range other.(*hyperLogLog).registers {
+		h.registers[i] = max(h.registers[i], register)
+	}
+}
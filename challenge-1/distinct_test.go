@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_distinctCounter(t *testing.T) {
+	testcases := map[string]struct {
+		inputs   []string
+		failed   int      // index of an input that fails, or -1
+		expected [][2]int // lines and words of each input, then of the total
+	}{
+		"Should count unique lines and words": {
+			inputs:   []string{"a b\na b\nb c\nlast"},
+			failed:   -1,
+			expected: [][2]int{{3, 4}, {3, 4}},
+		},
+		"Should union the inputs for the total": {
+			inputs:   []string{"x y\nz\n", "z\nw\n"},
+			failed:   -1,
+			expected: [][2]int{{2, 3}, {2, 2}, {3, 4}},
+		},
+		"Should leave a failed input out of the total": {
+			inputs:   []string{"x y\nz\n", "u v\nw\n", "z\n"},
+			failed:   1,
+			expected: [][2]int{{2, 3}, {2, 3}, {1, 1}, {2, 3}},
+		},
+	}
+
+	for k, v := range testcases {
+		for _, approx := range []bool{false, true} {
+			d := newDistinctCounter(approx)
+			var actual [][2]int
+			for i, input := range v.inputs {
+				d.Write([]byte(input))
+				lines, words := d.finish()
+				d.keep(i != v.failed)
+				actual = append(actual, [2]int{lines, words})
+			}
+			lines, words := d.totals()
+			actual = append(actual, [2]int{lines, words})
+			if fmt.Sprint(actual) != fmt.Sprint(v.expected) {
+				t.Error(k, "approx", approx, "Expected:", v.expected, "Actual", actual)
+			}
+		}
+	}
+}
+
+func Test_hyperLogLog(t *testing.T) {
+	d := newDistinctCounter(true)
+	for i := 0; i < 200000; i++ {
+		d.words.add([]byte(fmt.Sprint("word", i%100000)))
+	}
+	estimate := d.words.count()
+	if estimate < 97000 || estimate > 103000 {
+		t.Error("Expected: about 100000", "Actual", estimate)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+)
+
+// readFiles0From reads the NUL separated file names listed in the named
+// file, or in stdin when the name is "-".
+func readFiles0From(name string) ([]string, error) {
+	if name == stdinName {
+		names, err := readFiles0(os.Stdin)
+		for _, name := range names {
+			if name == stdinName {
+				return nil, errors.New("when reading file names from stdin, no file name of '-' allowed")
+			}
+		}
+		return names, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readFiles0(file)
+}
+
+// readFiles0 splits the input on NUL bytes, as written by find -print0. The
+// terminator after the last name is optional.
+func readFiles0(reader io.Reader) ([]string, error) {
+	var names []string
+	buffered := bufio.NewReader(reader)
+	for {
+		name, err := buffered.ReadString(0)
+		if err == io.EOF {
+			if name != "" {
+				names = append(names, name)
+			}
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name[:len(name)-1])
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_readFiles0(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected []string
+	}{
+		"Should split on NUL": {
+			input:    "a.txt\x00b c.txt\x00",
+			expected: []string{"a.txt", "b c.txt"},
+		},
+		"Should accept a missing last terminator": {
+			input:    "a.txt\x00b.txt",
+			expected: []string{"a.txt", "b.txt"},
+		},
+		"Should keep empty names": {
+			input:    "a.txt\x00\x00b.txt\x00",
+			expected: []string{"a.txt", "", "b.txt"},
+		},
+		"Should return nothing for empty input": {
+			input:    "",
+			expected: nil,
+		},
+	}
+
+	for k, v := range testcases {
+		actual, err := readFiles0(strings.NewReader(v.input))
+		if err != nil {
+			t.Error(k, err)
+		}
+		if strings.Join(actual, "|") != strings.Join(v.expected, "|") || len(actual) != len(v.expected) {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
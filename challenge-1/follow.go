@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+// follower counts a file as it grows, like tail -F piped into wc. The counts
+// cover everything read since it started: after a truncation the file is
+// read again from its start, and after a rotation the new file is counted
+// on top of the old one.
+type follower struct {
+	path    string
+	file    *os.File
+	offset  int64
+	opts    wc.Options
+	counter *wc.Counter
+	// before holds the counts of the data read before the last truncation
+	// or rotation.
+	before wc.Counts
+}
+
+func newFollower(path string, opts options) (*follower, error) {
+	count := withLanguage(path, opts).count
+	counter, err := wc.NewCounter(count)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &follower{path: path, file: file, opts: count, counter: counter}, nil
+}
+
+// counts returns the counts of everything read so far.
+func (f *follower) counts() wc.Counts {
+	result := f.before
+	result.Add(f.counter.Counts())
+	return result
+}
+
+// restart sets the counts so far aside and starts a new Counter, so that a
+// word or line left open at the end of the old data does not run on into
+// the new one.
+func (f *follower) restart() error {
+	counter, err := wc.NewCounter(f.opts)
+	if err != nil {
+		return err
+	}
+	f.counter.Flush()
+	f.before = f.counts()
+	f.counter = counter
+	f.offset = 0
+	return nil
+}
+
+// poll counts whatever was appended since the last poll, and reports
+// whether anything was read.
+func (f *follower) poll() (bool, error) {
+	read, err := f.drain()
+	if err != nil {
+		return read, err
+	}
+
+	meta, err := f.file.Stat()
+	if err != nil {
+		return read, err
+	}
+	if meta.Size() < f.offset {
+		// Truncated, start over from the beginning.
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return read, err
+		}
+		if err := f.restart(); err != nil {
+			return read, err
+		}
+		more, err := f.drain()
+		return read || more, err
+	}
+
+	current, err := os.Stat(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Rotated away and not created again yet.
+		return read, nil
+	}
+	if err != nil {
+		return read, err
+	}
+	if !os.SameFile(meta, current) {
+		file, err := os.Open(f.path)
+		if err != nil {
+			return read, err
+		}
+		f.file.Close()
+		f.file = file
+		if err := f.restart(); err != nil {
+			return read, err
+		}
+		more, err := f.drain()
+		return read || more, err
+	}
+	return read, nil
+}
+
+func (f *follower) drain() (bool, error) {
+	n, err := f.counter.ReadFrom(f.file)
+	f.offset += n
+	return n > 0, err
+}
+
+func (f *follower) close() error {
+	return f.file.Close()
+}
+
+// follow polls the files at every interval and reports their counts each
+// time one of them changed, until ctx is done or the counts cannot be
+// written.
+func follow(ctx context.Context, filePaths []string, opts options, out reporter) error {
+	var followers []*follower
+	defer func() {
+		for _, f := range followers {
+			f.close()
+		}
+	}()
+	for _, filePath := range filePaths {
+		f, err := newFollower(filePath, opts)
+		if err != nil {
+			return err
+		}
+		followers = append(followers, f)
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		changed := first
+		for _, f := range followers {
+			read, err := f.poll()
+			if err != nil {
+				return err
+			}
+			changed = changed || read
+		}
+
+		if changed {
+			var total wc.Counts
+			for _, f := range followers {
+				result := f.counts()
+				if err := out.report(result, f.path); err != nil {
+					return err
+				}
+				total.Add(result)
+			}
+			if err := out.total(total, len(followers)); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+func Test_followerPoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("one two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := newFollower(path, options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.close()
+
+	steps := []struct {
+		name     string
+		change   func() error
+		read     bool
+		expected wc.Counts
+	}{
+		{
+			name:     "Should count the existing data",
+			change:   func() error { return nil },
+			read:     true,
+			expected: wc.Counts{Lines: 1, Words: 2, Chars: 8, Bytes: 8, MaxLineLength: 7},
+		},
+		{
+			name:     "Should not change without new data",
+			change:   func() error { return nil },
+			read:     false,
+			expected: wc.Counts{Lines: 1, Words: 2, Chars: 8, Bytes: 8, MaxLineLength: 7},
+		},
+		{
+			name:     "Should count appended data",
+			change:   func() error { return appendFile(path, "three\n") },
+			read:     true,
+			expected: wc.Counts{Lines: 2, Words: 3, Chars: 14, Bytes: 14, MaxLineLength: 7},
+		},
+		{
+			name:     "Should read a truncated file from the start",
+			change:   func() error { return os.WriteFile(path, []byte("four\n"), 0644) },
+			read:     true,
+			expected: wc.Counts{Lines: 3, Words: 4, Chars: 19, Bytes: 19, MaxLineLength: 7},
+		},
+		{
+			name: "Should follow a rotated file",
+			change: func() error {
+				if err := appendFile(path, "five\n"); err != nil {
+					return err
+				}
+				if err := os.Rename(path, path+".1"); err != nil {
+					return err
+				}
+				return os.WriteFile(path, []byte("six seven\n"), 0644)
+			},
+			read:     true,
+			expected: wc.Counts{Lines: 5, Words: 7, Chars: 34, Bytes: 34, MaxLineLength: 9},
+		},
+		{
+			name:     "Should wait while the rotated file is missing",
+			change:   func() error { return os.Rename(path, path+".2") },
+			read:     false,
+			expected: wc.Counts{Lines: 5, Words: 7, Chars: 34, Bytes: 34, MaxLineLength: 9},
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatal(step.name, err)
+		}
+		read, err := f.poll()
+		if err != nil {
+			t.Error(step.name, err)
+		}
+		if read != step.read {
+			t.Error(step.name, "Expected read:", step.read, "Actual", read)
+		}
+		if actual := f.counts(); actual != step.expected {
+			t.Error(step.name, "Expected:", step.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_followerRestart(t *testing.T) {
+	// The old data ends in the middle of a word and of a line, which must
+	// not run on into the new data.
+	changes := map[string]func(path string) error{
+		"Should start over after a truncation": func(path string) error {
+			return os.WriteFile(path, []byte("cd\n"), 0644)
+		},
+		"Should start over after a rotation": func(path string) error {
+			if err := os.Rename(path, path+".1"); err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte("cd\n"), 0644)
+		},
+	}
+
+	for k, change := range changes {
+		path := filepath.Join(t.TempDir(), "app.log")
+		if err := os.WriteFile(path, []byte("x\tyz ab"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := newFollower(path, options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.poll(); err != nil {
+			t.Error(k, err)
+		}
+		if err := change(path); err != nil {
+			t.Fatal(k, err)
+		}
+		if _, err := f.poll(); err != nil {
+			t.Error(k, err)
+		}
+		expected := wc.Counts{Lines: 2, Words: 4, Chars: 10, Bytes: 10, MaxLineLength: 13}
+		if actual := f.counts(); actual != expected {
+			t.Error(k, "Expected:", expected, "Actual", actual)
+		}
+		f.close()
+	}
+}
+
+func Test_followWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("one two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// follow must stop at the first failed write rather than poll forever.
+	failure := errors.New("broken pipe")
+	opts := options{lines: true, format: formatTable, interval: time.Hour}
+	table := &tableReporter{w: failingWriter{failure}, columns: selectedColumns(opts), width: 1}
+	if err := follow(context.Background(), []string{path}, opts, table); !errors.Is(err, failure) {
+		t.Error("Expected:", failure, "Actual", err)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func appendFile(path, data string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(data)
+	return err
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ignoreRules holds the patterns of one .gitignore file.
+type ignoreRules struct {
+	dir      string // directory of the .gitignore, relative to the walk root
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// parseGitignore reads the patterns of a .gitignore file found in dir,
+// following the rules of gitignore(5): blank lines and # comments are
+// skipped, ! negates, a trailing / only matches directories, and a pattern
+// with a / in it is relative to dir while any other matches at any depth.
+func parseGitignore(reader io.Reader, dir string) (ignoreRules, error) {
+	rules := ignoreRules{dir: dir}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var pattern ignorePattern
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			// Git ignores patterns it cannot make sense of.
+			continue
+		}
+		pattern.re = re
+		rules.patterns = append(rules.patterns, pattern)
+	}
+	return rules, scanner.Err()
+}
+
+// globToRegexp translates a gitignore glob, where * and ? never match a /
+// and ** matches any number of directories.
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**") && i+2 == len(glob) && (i == 0 || glob[i-1] == '/'):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return expr.String()
+}
+
+// isIgnored reports whether the path, relative to the walk root and slash
+// separated, is ignored by the rules of its directory and the ones above
+// it. The last matching pattern wins, so deeper files override upper ones.
+func isIgnored(rules []ignoreRules, rel string, isDir bool) bool {
+	ignored := false
+	for _, r := range rules {
+		local := rel
+		if r.dir != "" {
+			if !strings.HasPrefix(rel, r.dir+"/") {
+				continue
+			}
+			local = strings.TrimPrefix(rel, r.dir+"/")
+		}
+		for _, pattern := range r.patterns {
+			if pattern.dirOnly && !isDir {
+				continue
+			}
+			if pattern.re.MatchString(local) {
+				ignored = !pattern.negate
+			}
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_isIgnored(t *testing.T) {
+	root, err := parseGitignore(strings.NewReader("# build output\n\n/bin\n*.log\nvendor/\ndocs/**/*.tmp\n!keep.log\n"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := parseGitignore(strings.NewReader("*.txt\n!keep.txt\n"), "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []ignoreRules{root, nested}
+
+	testcases := map[string]struct {
+		rel      string
+		isDir    bool
+		expected bool
+	}{
+		"Should ignore an anchored path":                  {rel: "bin", isDir: true, expected: true},
+		"Should not match an anchored path deeper":        {rel: "src/bin", isDir: true, expected: false},
+		"Should ignore a base name glob at any depth":     {rel: "src/a/debug.log", expected: true},
+		"Should re-include a negated file":                {rel: "logs/keep.log", expected: false},
+		"Should ignore a directory only pattern":          {rel: "a/vendor", isDir: true, expected: true},
+		"Should not apply a directory pattern to a file":  {rel: "vendor", expected: false},
+		"Should match ** across directories":              {rel: "docs/a/b/c.tmp", expected: true},
+		"Should match ** with no directory":               {rel: "docs/c.tmp", expected: true},
+		"Should apply nested rules below their directory": {rel: "src/notes.txt", expected: true},
+		"Should not apply nested rules outside":           {rel: "notes.txt", expected: false},
+		"Should let nested negations override":            {rel: "src/keep.txt", expected: false},
+		"Should not ignore a file no pattern matches":     {rel: "src/main.go", expected: false},
+	}
+
+	for k, v := range testcases {
+		actual := isIgnored(rules, v.rel, v.isDir)
+		if actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
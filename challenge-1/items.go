@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	byWord = "word"
+	byLine = "line"
+	byChar = "char"
+)
+
+// itemWriter splits the data written to it into words, lines or characters
+// and passes each one to emit. Words are split on Unicode white space, and
+// newlines are not characters, as with grep -o . | sort | uniq -c.
+type itemWriter struct {
+	by      string
+	emit    func(item []byte)
+	current []byte
+	partial []byte // incomplete rune left at the end of the previous write
+}
+
+func newItemWriter(by string, emit func(item []byte)) (*itemWriter, error) {
+	switch by {
+	case byWord, byLine, byChar:
+		return &itemWriter{by: by, emit: emit}, nil
+	}
+	return nil, fmt.Errorf("invalid item %q, expecting word, line or char", by)
+}
+
+// Write splits p. It never fails.
+func (w *itemWriter) Write(p []byte) (int, error) {
+	rest := p
+	if len(w.partial) > 0 {
+		// Finish the rune cut by the previous write with the start of p,
+		// rather than copy all of p after it.
+		carried := len(w.partial)
+		joined := append(w.partial, p[:min(len(p), utf8.UTFMax)]...)
+		w.partial = nil
+		i := 0
+		for i < carried && utf8.FullRune(joined[i:]) {
+			i += w.split(joined[i:])
+		}
+		if i < carried {
+			// p is too short to finish it, and is all in joined.
+			w.partial = append([]byte(nil), joined[i:]...)
+			return len(p), nil
+		}
+		rest = p[i-carried:]
+	}
+
+	for i := 0; i < len(rest); {
+		if !utf8.FullRune(rest[i:]) {
+			w.partial = append([]byte(nil), rest[i:]...)
+			break
+		}
+		i += w.split(rest[i:])
+	}
+	return len(p), nil
+}
+
+// split passes on the rune at the start of data, which is complete, and
+// returns its width.
+func (w *itemWriter) split(data []byte) int {
+	r, width := utf8.DecodeRune(data)
+	w.add(r, data[:width])
+	return width
+}
+
+func (w *itemWriter) add(r rune, encoded []byte) {
+	switch w.by {
+	case byChar:
+		if r != '\n' {
+			w.emit(encoded)
+		}
+	case byLine:
+		if r == '\n' {
+			w.emit(w.current)
+			w.current = w.current[:0]
+			return
+		}
+		w.current = append(w.current, encoded...)
+	case byWord:
+		if unicode.IsSpace(r) {
+			w.flushCurrent()
+			return
+		}
+		w.current = append(w.current, encoded...)
+	}
+}
+
+// Flush emits the item left at the end of an input, so that no item spans
+// two inputs.
+func (w *itemWriter) Flush() {
+	for _, b := range w.partial {
+		w.add(utf8.RuneError, []byte{b})
+	}
+	w.partial = nil
+	w.flushCurrent()
+}
+
+func (w *itemWriter) flushCurrent() {
+	if len(w.current) > 0 {
+		w.emit(w.current)
+		w.current = w.current[:0]
+	}
+}
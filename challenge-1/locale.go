@@ -0,0 +1,28 @@
+package main
+
+import "strings"
+
+// charsAreBytes reports whether the locale set in the environment counts
+// every byte as a character, as wc -m does in the C locale and in locales
+// with a single byte encoding. The variables are checked in the order the C
+// library does.
+func charsAreBytes(getenv func(string) string) bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			return !isUTF8Locale(locale)
+		}
+	}
+	return true
+}
+
+// isUTF8Locale reports whether a locale name such as en_US.UTF-8 or
+// C.utf8@euro uses the UTF-8 codeset.
+func isUTF8Locale(locale string) bool {
+	_, codeset, found := strings.Cut(locale, ".")
+	if !found {
+		return false
+	}
+	codeset, _, _ = strings.Cut(codeset, "@")
+	codeset = strings.ToLower(strings.ReplaceAll(codeset, "-", ""))
+	return codeset == "utf8"
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func Test_charsAreBytes(t *testing.T) {
+	testcases := map[string]struct {
+		env      map[string]string
+		expected bool
+	}{
+		"Should count bytes without a locale":    {env: map[string]string{}, expected: true},
+		"Should count bytes in the C locale":     {env: map[string]string{"LANG": "C"}, expected: true},
+		"Should count bytes in latin1 locales":   {env: map[string]string{"LANG": "en_US.ISO-8859-1"}, expected: true},
+		"Should decode in UTF-8 locales":         {env: map[string]string{"LANG": "en_US.UTF-8"}, expected: false},
+		"Should accept utf8 spelled differently": {env: map[string]string{"LANG": "C.utf8@euro"}, expected: false},
+		"Should prefer LC_ALL to LANG":           {env: map[string]string{"LC_ALL": "POSIX", "LANG": "en_US.UTF-8"}, expected: true},
+		"Should prefer LC_CTYPE to LANG":         {env: map[string]string{"LC_CTYPE": "en_GB.UTF-8", "LANG": "C"}, expected: false},
+	}
+
+	for k, v := range testcases {
+		getenv := func(name string) string { return v.env[name] }
+		if actual := charsAreBytes(getenv); actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
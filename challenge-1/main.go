@@ -1,176 +1,492 @@
 package main
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
-	"sync"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
 )
 
-func main() {
-	var (
-		reader io.Reader
-		err    error
-	)
+type options struct {
+	lines bool
+	words bool
+	chars bool
+	bytes bool
 
-	filePath, arg := parseInput()
-	if filePath != "" {
-		reader, err = os.Open(filePath)
+	maxLineLength bool
+
+	count wc.Options
+
+	raw        bool
+	parallel   bool
+	follow     bool
+	recursive  bool
+	byExt      bool
+	classify   bool
+	top        int
+	by         string
+	ignoreCase bool
+	stopWords  string
+	approx     bool
+	// frequencies builds the --top table and distinct counts the --distinct
+	// items while the inputs are counted.
+	frequencies *topCounter
+	distinct    *distinctCounter
+
+	progress bool
+	stats    bool
+	// meter measures the bytes read for --progress and --stats.
+	meter      *meter
+	include    globList
+	exclude    globList
+	interval   time.Duration
+	files0From string
+	format     string
+}
+
+func main() {
+	filePaths, opts := parseInput()
+	if opts.files0From != "" {
+		if len(filePaths) > 0 {
+			fmt.Fprintln(os.Stderr, "extra operand", filePaths[0])
+			fmt.Fprintln(os.Stderr, "file operands cannot be combined with --files0-from")
+			os.Exit(1)
+		}
+		var err error
+		filePaths, err = readFiles0From(opts.files0From)
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-	} else {
-		meta, err := os.Stdin.Stat()
+	}
+
+	out, err := newReporter(os.Stdout, opts, filePaths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if opts.byExt {
+		out = newExtensionReporter(out)
+	}
+	if opts.top > 0 {
+		if opts.follow {
+			fmt.Fprintln(os.Stderr, "--top cannot be combined with --follow")
+			os.Exit(1)
+		}
+		opts.frequencies, err = newTopCounter(opts)
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		out = discardReporter{}
+	}
+	if opts.distinct != nil && opts.follow {
+		fmt.Fprintln(os.Stderr, "--distinct cannot be combined with --follow")
+		os.Exit(1)
+	}
+
+	if opts.follow {
+		if len(filePaths) == 0 {
+			fmt.Fprintln(os.Stderr, "--follow needs at least one file")
 			os.Exit(1)
 		}
-		if meta.Size() > 0 {
-			reader = os.Stdin
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := follow(ctx, filePaths, opts, out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		return
 	}
 
-	if reader == nil {
-		fmt.Println("No source found")
-		os.Exit(1)
+	if opts.progress || opts.stats {
+		opts.meter = newMeter(knownSize(filePaths))
+	}
+	if opts.progress {
+		opts.meter.showProgress(os.Stderr)
+		out = progressReporter{out: out, meter: opts.meter}
 	}
 
-	lines, words, chars, bytes, err := countFrom(reader)
-	if err != nil {
-		fmt.Println(err)
+	failed := countInputs(out, os.Stderr, filePaths, opts)
+	opts.meter.stopProgress()
+	printTop(opts)
+	if opts.stats {
+		fmt.Fprintln(os.Stderr, statsLine(opts.meter.done.Load(), time.Since(opts.meter.start)))
+	}
+
+	if failed {
 		os.Exit(1)
 	}
+}
 
-	switch arg {
-	case "l":
-		fmt.Println(lines, filePath)
-	case "w":
-		fmt.Println(words, filePath)
-	case "c":
-		fmt.Println(bytes, filePath)
-	case "m":
-		fmt.Println(chars, filePath)
-	default:
-		fmt.Println(lines, words, chars, bytes, filePath)
+// countInputs counts the files, or stdin when there are no operands. An empty
+// --files0-from list counts nothing, as with wc.
+func countInputs(out reporter, errs io.Writer, filePaths []string, opts options) bool {
+	if len(filePaths) == 0 && opts.files0From == "" {
+		return countStdin(out, errs, opts)
 	}
+	return countFiles(out, errs, filePaths, opts)
 }
 
-func parseInput() (string, string) {
+// countFiles counts and reports every file, then the total. An error with a
+// file is written to errs and the other files are still counted, as wc does;
+// countFiles reports whether any of them failed.
+func countFiles(out reporter, errs io.Writer, filePaths []string, opts options) bool {
 	var (
-		filePath string
-		argument string
+		total  wc.Counts
+		inputs int
+		failed bool
 	)
 
-	initializeFlags()
-	filePath = flag.Arg(0)
+	for _, filePath := range filePaths {
+		if opts.recursive && isDirectory(filePath) {
+			w := walker{opts: opts, out: out, errs: errs}
+			total.Add(w.walk(filePath))
+			inputs += w.files
+			failed = failed || w.failed
+			continue
+		}
 
-	if flag.NFlag() > 0 {
-		flag.Visit(func(f *flag.Flag) {
-			argument = f.Name
-			filePath = f.Value.String()
-		})
+		inputs++
+		result, err := countFile(filePath, opts)
+		if err == nil {
+			err = out.report(result, filePath)
+		}
+		opts.distinct.keep(err == nil)
+		if err != nil {
+			fmt.Fprintln(errs, err)
+			failed = true
+			continue
+		}
+		total.Add(result)
 	}
+	if opts.distinct != nil {
+		total.DistinctLines, total.DistinctWords = opts.distinct.totals()
+	}
+	if err := out.total(total, inputs); err != nil {
+		fmt.Fprintln(errs, err)
+		failed = true
+	}
+	return failed
+}
 
-	return filePath, argument
+// countStdin counts and reports stdin, and reports whether it failed.
+func countStdin(out reporter, errs io.Writer, opts options) bool {
+	result, err := countStdinStream(opts)
+	if err == nil {
+		err = out.report(result, "")
+	}
+	opts.distinct.keep(err == nil)
+	if err == nil {
+		err = out.total(result, 1)
+	}
+	if err != nil {
+		fmt.Fprintln(errs, err)
+		return true
+	}
+	return false
 }
 
-func countFrom(reader io.Reader) (int, int, int, int, error) {
-	var (
-		lines int
-		words int
-		chars int
-		bytes int
-	)
+// printTop writes the frequency table of --top, if any.
+func printTop(opts options) {
+	if opts.frequencies == nil {
+		return
+	}
+	if err := writeTop(os.Stdout, opts.format, opts.frequencies.table.top(opts.top)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	pr1, pw1 := io.Pipe()
-	pr2, pw2 := io.Pipe()
-	pr3, pw3 := io.Pipe()
-	pr4, pw4 := io.Pipe()
-
-	var wg sync.WaitGroup
-	wg.Add(5)
-
-	writer := io.MultiWriter(pw1, pw2, pw3, pw4)
-	go func() {
-		defer wg.Done()
-		defer pw1.Close()
-		defer pw2.Close()
-		defer pw3.Close()
-		defer pw4.Close()
-		io.Copy(writer, reader)
-	}()
+func countFile(filePath string, opts options) (wc.Counts, error) {
+	if filePath == "" {
+		return wc.Counts{}, errors.New("invalid zero-length file name")
+	}
+	if filePath == stdinName {
+		return countStdinStream(opts)
+	}
+	opts = withLanguage(filePath, opts)
 
-	go func() {
-		defer wg.Done()
-		defer pw1.Close()
-		count("l", pr1, &lines)
-	}()
+	result, err := countRegular(filePath, opts)
+	var pathErr *fs.PathError
+	if err != nil && !errors.As(err, &pathErr) {
+		// Name the file in decompression and other errors, as open and
+		// read errors already do.
+		err = fmt.Errorf("%s: %w", filePath, err)
+	}
+	return result, err
+}
 
-	go func() {
-		defer wg.Done()
-		defer pw2.Close()
-		count("w", pr2, &words)
-	}()
+func countRegular(filePath string, opts options) (wc.Counts, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return wc.Counts{}, err
+	}
+	defer file.Close()
 
-	go func() {
-		defer wg.Done()
-		defer pw3.Close()
-		count("c", pr3, &bytes)
-	}()
+	meta, err := file.Stat()
+	if err != nil {
+		return wc.Counts{}, err
+	}
+	if !meta.Mode().IsRegular() || meta.Size() == 0 {
+		// Pipes, devices and files such as those of /proc, which have no
+		// size up front, are read until EOF.
+		return countStream(opts.meter.wrap(file), opts)
+	}
 
-	go func() {
-		defer wg.Done()
-		defer pw4.Close()
-		count("m", pr4, &chars)
+	size := meta.Size()
+	plain := opts.raw || !isCompressed(file)
+	if plain && onlyCountsBytes(opts) {
+		// Like GNU wc, trust the size of a regular file rather than read it.
+		opts.meter.add(size)
+		return wc.Counts{Bytes: int(size), Chars: int(size)}, nil
+	}
+	if plain && opts.parallel && opts.frequencies == nil && opts.distinct == nil && wc.CanCountParallel(file, opts.count) {
+		chunks := min(runtime.NumCPU(), int(size/wc.MinChunkSize))
+		count := opts.count
+		if opts.meter != nil {
+			count.Progress = opts.meter.add
+		}
+		return wc.CountParallel(file, size, max(chunks, 1), count)
+	}
+	if !plain {
+		return countStream(opts.meter.wrap(file), opts)
+	}
+	if data, unmap, err := mapFile(file, size); err == nil {
+		defer unmap()
+		// Already known not to be compressed.
+		opts.raw = true
+		return countMapped(data, opts)
+	}
+	return countStream(opts.meter.wrap(file), opts)
+}
+
+// onlyCountsBytes reports whether every selected column is the byte count,
+// which is characters too in the C locale.
+func onlyCountsBytes(opts options) bool {
+	if opts.lines || opts.words || opts.maxLineLength || opts.classify {
+		return false
+	}
+	if opts.chars && !opts.count.CharsAreBytes {
+		return false
+	}
+	return opts.frequencies == nil && opts.distinct == nil
+}
+
+// countMapped counts a memory-mapped file that is not compressed. Plain
+// counting goes over the mapped bytes directly, anything else reads them as
+// a stream.
+func countMapped(data []byte, opts options) (result wc.Counts, err error) {
+	// A file truncated while mapped faults instead of reaching EOF; make
+	// that an error rather than a crash.
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			if _, fault := r.(interface{ Addr() uintptr }); !fault {
+				panic(r)
+			}
+			err = errors.New("file changed size while being counted")
+		}
 	}()
 
-	wg.Wait()
-	return lines, words, chars, bytes, nil
+	if opts.frequencies != nil || opts.distinct != nil || opts.meter != nil {
+		return countStream(opts.meter.wrap(bytes.NewReader(data)), opts)
+	}
+	return wc.CountBytes(data, opts.count)
 }
 
-func count(argument string, reader io.Reader, result *int) error {
-	var splitFunc bufio.SplitFunc
+// withLanguage sets the language to classify lines by for --classify, from
+// the extension of the file. Files of other languages count no blank,
+// comment or code lines.
+func withLanguage(filePath string, opts options) options {
+	if opts.classify {
+		opts.count.Language = wc.LanguageFor(filePath)
+	}
+	return opts
+}
 
-	switch argument {
-	case "c":
-		splitFunc = bufio.ScanBytes
-	case "w":
-		splitFunc = bufio.ScanWords
-	case "l":
-		splitFunc = bufio.ScanLines
-	case "m":
-		splitFunc = bufio.ScanRunes
-	default:
-		return errors.New(fmt.Sprintln("Invalid argument:", argument))
+// countStream counts reader until EOF, decompressing it first unless --raw
+// is set. The data also goes to the --top frequency table and the --distinct
+// sets.
+func countStream(reader io.Reader, opts options) (wc.Counts, error) {
+	if !opts.raw {
+		decompressed, err := decompress(reader)
+		if err != nil {
+			return wc.Counts{}, err
+		}
+		reader = decompressed
+	}
+	if opts.frequencies != nil {
+		reader = io.TeeReader(reader, opts.frequencies)
+		defer opts.frequencies.Flush()
+	}
+	if opts.distinct == nil {
+		return wc.Count(reader, opts.count)
 	}
 
-	scanner := bufio.NewScanner(reader)
-	scanner.Split(splitFunc)
-	count := 0
-	for scanner.Scan() {
-		scanner.Text()
-		count += 1
+	result, err := wc.Count(io.TeeReader(reader, opts.distinct), opts.count)
+	result.DistinctLines, result.DistinctWords = opts.distinct.finish()
+	return result, err
+}
+
+func parseInput() ([]string, options) {
+	selected := initializeFlags()
+	files0From := flag.String("files0-from", "", "Read input file names separated by NUL from the given file, - for stdin")
+	format := flag.String("format", formatTable, "Output format: table, json or csv")
+	interval := flag.Duration("interval", time.Second, "How often --follow checks for new data")
+	var opts options
+	flag.Var(&opts.include, "include", "With -r, only count the files matching this glob, can be repeated")
+	flag.Var(&opts.exclude, "exclude", "With -r, skip the files and directories matching this glob, can be repeated")
+	top := flag.Int("top", 0, "Print the N most frequent items with their counts instead of the counts")
+	by := flag.String("by", byWord, "What --top counts: word, line or char")
+	stopWords := flag.String("stop-words", "", "Leave out of --top the items listed in this file, one per line")
+	wordMode := flag.String("word-mode", wc.WordsSpace, "How words are split: space for Unicode white space, posix for the POSIX white space characters, uax29 for Unicode word boundaries")
+	flag.CommandLine.Parse(expandShortFlags(os.Args[1:]))
+
+	opts.lines = *selected["l"]
+	opts.words = *selected["w"]
+	opts.chars = *selected["m"]
+	opts.bytes = *selected["c"]
+	opts.maxLineLength = *selected["L"] || *selected["max-line-length"]
+	if !opts.lines && !opts.words && !opts.chars && !opts.bytes && !opts.maxLineLength {
+		// Same as wc, no flags means -l -w -c.
+		opts.lines, opts.words, opts.bytes = true, true, true
+	}
+	opts.parallel = *selected["parallel"]
+	opts.raw = *selected["raw"]
+	opts.follow = *selected["follow"]
+	opts.recursive = *selected["r"]
+	opts.byExt = *selected["by-ext"]
+	opts.classify = *selected["classify"]
+	opts.top = *top
+	opts.by = *by
+	opts.ignoreCase = *selected["ignore-case"]
+	opts.stopWords = *stopWords
+	opts.approx = *selected["approx"]
+	opts.progress = *selected["progress"]
+	opts.stats = *selected["stats"]
+	if *selected["distinct"] {
+		opts.distinct = newDistinctCounter(opts.approx)
+	}
+	opts.interval = *interval
+	opts.files0From = *files0From
+	opts.format = *format
+	opts.count = wc.Options{
+		WordMode:      *wordMode,
+		CharsAreBytes: charsAreBytes(os.Getenv),
+	}
+	if err := checkOptions(opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return flag.Args(), opts
+}
+
+// checkOptions rejects the flag values that parse but make no sense.
+func checkOptions(opts options) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("invalid interval %s, expecting a positive duration", opts.interval)
+	}
+	if opts.top < 0 {
+		return fmt.Errorf("invalid top %d, expecting a count of items or 0 for none", opts.top)
 	}
-	*result = count
 	return nil
 }
 
+// countFrom counts every metric of reader in a single buffered read loop.
+func countFrom(reader io.Reader) (int, int, int, int, error) {
+	result, err := wc.Count(reader, wc.Options{})
+	return result.Lines, result.Words, result.Chars, result.Bytes, err
+}
+
 func availableFlags() map[string]string {
 	return map[string]string{
 		"c": "Count bytes in the given file",
 		"w": "Count words in the given file",
 		"l": "Count lines in the given file",
 		"m": "Count characters in the given file",
+		"L": "Print the maximum display width of a line",
+		"r": "Count the text files under directories, with a subtotal per directory",
+
+		"max-line-length": "Same as -L",
+		"parallel":        "Count regular files in chunks on every CPU",
+		"classify":        "Also count blank, comment and code lines of Go, Python, JavaScript, shell and JSON files",
+		"ignore-case":     "Fold case when counting --top items",
+		"approx":          "Estimate in bounded memory, --top counts with a count-min sketch and --distinct ones with HyperLogLog",
+		"distinct":        "Also count the unique lines and words; the total counts them across all the inputs",
+		"progress":        "Show the bytes read, percentage, rate and time left on stderr while counting",
+		"stats":           "Print the time taken and the throughput on stderr after counting",
+		"by-ext":          "Print one row per file extension, most lines first, instead of one per file",
+		"follow":          "Keep counting data appended to the files, like tail -F",
+		"raw":             "Count gzip and bzip2 input as is instead of decompressing it",
 	}
 }
 
-func initializeFlags() {
+func initializeFlags() map[string]*bool {
+	selected := map[string]*bool{}
 	for k, v := range availableFlags() {
-		flag.String(k, "", v)
+		selected[k] = flag.Bool(k, false, v)
+	}
+	return selected
+}
+
+// expandShortFlags splits bundled short options such as -lw into -l -w, since
+// the flag package only understands one flag per argument.
+func expandShortFlags(args []string) []string {
+	flags := availableFlags()
+	var expanded []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return append(expanded, args[i:]...)
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if takesValue(name) && i+1 < len(args) {
+			// The next argument is the value of this flag, not an operand.
+			expanded = append(expanded, arg, args[i+1])
+			i++
+			continue
+		}
+
+		bundle := strings.TrimPrefix(arg, "-")
+		if len(bundle) < 2 || !isShortFlagBundle(bundle, flags) {
+			expanded = append(expanded, arg)
+			continue
+		}
+		for _, r := range bundle {
+			expanded = append(expanded, "-"+string(r))
+		}
+	}
+	return expanded
+}
+
+func takesValue(name string) bool {
+	f := flag.CommandLine.Lookup(name)
+	if f == nil {
+		return false
+	}
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !boolFlag.IsBoolFlag()
+}
+
+func isShortFlagBundle(bundle string, flags map[string]string) bool {
+	for _, r := range bundle {
+		if _, ok := flags[string(r)]; !ok {
+			return false
+		}
 	}
-	flag.Parse()
+	return true
 }
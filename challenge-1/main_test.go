@@ -1,51 +1,17 @@
 package main
 
 import (
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
-)
-
-func Test_count(t *testing.T) {
-	testcases := map[string]struct {
-		expected int
-		argument string
-		input    string
-	}{
-		"Should return number of bytes": {
-			argument: "c",
-			input:    "one two three ท",
-			expected: 17,
-		},
-		"Should return number of words": {
-			argument: "w",
-			input:    "one two three",
-			expected: 3,
-		},
-		"Should return number of lines": {
-			argument: "l",
-			input:    "one two three\nfour five six",
-			expected: 2,
-		},
-		"Should return number of characters": {
-			argument: "m",
-			input:    "one two three ท",
-			expected: 15,
-		},
-	}
+	"testing/iotest"
+	"time"
 
-	for k, v := range testcases {
-		reader := strings.NewReader(v.input)
-		var actual int
-		err := count(v.argument, reader, &actual)
-		if err != nil {
-			t.Error(k, err)
-		}
-		if actual != v.expected {
-			t.Error(k, "Expected:", v.expected, "Actual", actual)
-		}
-	}
-}
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
 
 func Test_CountFrom(t *testing.T) {
 	reader, err := os.Open("file.txt")
@@ -74,3 +40,144 @@ func Test_CountFrom(t *testing.T) {
 		t.Error("Expected chars", 3566, "Actual chars", actualChars)
 	}
 }
+
+func Test_expandShortFlags(t *testing.T) {
+	testcases := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"Should split bundled flags": {
+			args:     []string{"-lw", "file.txt"},
+			expected: []string{"-l", "-w", "file.txt"},
+		},
+		"Should keep separate flags": {
+			args:     []string{"-l", "-c", "file.txt"},
+			expected: []string{"-l", "-c", "file.txt"},
+		},
+		"Should not split unknown flags": {
+			args:     []string{"-lx", "file.txt"},
+			expected: []string{"-lx", "file.txt"},
+		},
+		"Should stop at the first operand": {
+			args:     []string{"-l", "file.txt", "-wc"},
+			expected: []string{"-l", "file.txt", "-wc"},
+		},
+	}
+
+	for k, v := range testcases {
+		actual := expandShortFlags(v.args)
+		if strings.Join(actual, " ") != strings.Join(v.expected, " ") {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_countFiles(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.gz")
+	if err := os.WriteFile(corrupt, []byte("\x1f\x8b\x08\x00broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+
+	var out, errs strings.Builder
+	opts := options{lines: true, format: formatTable}
+	table := &tableReporter{w: &out, columns: selectedColumns(opts), width: 1}
+	failed := countFiles(table, &errs, []string{"file.txt", missing, dir, corrupt, "file.txt"}, opts)
+
+	if !failed {
+		t.Error("Expected a failure")
+	}
+	expected := "23 file.txt\n23 file.txt\n46 total\n"
+	if out.String() != expected {
+		t.Error("Expected:", expected, "Actual", out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(errs.String()), "\n")
+	for i, name := range []string{missing, dir, corrupt} {
+		if i >= len(lines) || !strings.Contains(lines[i], name) {
+			t.Error("Expected an error naming", name, "Actual", lines)
+		}
+	}
+}
+
+func Test_countStreamReadError(t *testing.T) {
+	failure := errors.New("input/output error")
+	reader := io.MultiReader(strings.NewReader("one two\n"), iotest.ErrReader(failure))
+	if _, err := countStream(reader, options{raw: true}); !errors.Is(err, failure) {
+		t.Error("Expected:", failure, "Actual", err)
+	}
+}
+
+func Test_onlyCountsBytes(t *testing.T) {
+	testcases := map[string]struct {
+		opts     options
+		expected bool
+	}{
+		"Should take the size for -c":             {opts: options{bytes: true}, expected: true},
+		"Should take the size for -m in C locale": {opts: options{chars: true, count: wc.Options{CharsAreBytes: true}}, expected: true},
+		"Should read for -m in UTF-8":             {opts: options{chars: true}, expected: false},
+		"Should read for lines":                   {opts: options{bytes: true, lines: true}, expected: false},
+		"Should read for distinct items":          {opts: options{bytes: true, distinct: newDistinctCounter(false)}, expected: false},
+	}
+
+	for k, v := range testcases {
+		if actual := onlyCountsBytes(v.opts); actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+
+	// The size of a compressed file is not the size of its content.
+	result, err := countFile("testdata/file.txt.gz", options{bytes: true})
+	if err != nil || result.Bytes != 3568 {
+		t.Error("Expected:", 3568, "Actual", result.Bytes, err)
+	}
+}
+
+func Test_checkOptions(t *testing.T) {
+	testcases := map[string]struct {
+		opts  options
+		valid bool
+	}{
+		"Should accept a positive interval": {opts: options{interval: time.Second}, valid: true},
+		"Should reject a zero interval":     {opts: options{interval: 0}},
+		"Should reject a negative interval": {opts: options{interval: -time.Second}},
+		"Should accept --top":               {opts: options{interval: time.Second, top: 10}, valid: true},
+		"Should reject a negative --top":    {opts: options{interval: time.Second, top: -1}},
+	}
+
+	for k, v := range testcases {
+		err := checkOptions(v.opts)
+		if (err == nil) != v.valid {
+			t.Error(k, "Expected:", v.valid, "Actual", err)
+		}
+	}
+}
+
+func Test_countInputsEmptyFiles0From(t *testing.T) {
+	var out, errs strings.Builder
+	opts := options{lines: true, format: formatTable, files0From: "list"}
+	table := &tableReporter{w: &out, columns: selectedColumns(opts), width: 1}
+	if failed := countInputs(table, &errs, nil, opts); failed {
+		t.Error("Expected no failure, Actual", errs.String())
+	}
+	if out.String() != "" {
+		t.Error("Expected nothing counted, Actual", out.String())
+	}
+}
+
+func Test_countFileUTF16(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "utf16.txt")
+	// A byte order mark, then "hé\n" in UTF-16 little endian.
+	if err := os.WriteFile(path, []byte("\xff\xfeh\x00\xe9\x00\n\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, raw := range []bool{false, true} {
+		actual, err := countFile(path, options{chars: true, words: true, raw: raw})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual.Chars != 3 || actual.Words != 1 {
+			t.Error("raw", raw, "Expected:", 3, 1, "Actual", actual.Chars, actual.Words)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the size bytes of file read only, and tells the kernel they
+// will be read in order so that it reads ahead.
+func mapFile(file *os.File, size int64) ([]byte, func() error, error) {
+	if int64(int(size)) != size {
+		return nil, nil, errors.New("file too large to map")
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	syscall.Madvise(data, syscall.MADV_SEQUENTIAL)
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
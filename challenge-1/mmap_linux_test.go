@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_countMapped(t *testing.T) {
+	file, err := os.Open("file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	meta, _ := file.Stat()
+
+	data, unmap, err := mapFile(file, meta.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unmap()
+
+	opts := options{raw: true}
+	expected, _ := countStream(file, opts)
+	for k, v := range map[string]options{
+		"Should count over the mapped bytes": opts,
+		"Should stream the mapped bytes":     {raw: true, meter: newMeter(0)},
+	} {
+		actual, err := countMapped(data, v)
+		if err != nil {
+			t.Error(k, err)
+		}
+		if actual != expected {
+			t.Error(k, "Expected:", expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_countMappedTruncated(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "shrinking.txt")
+	if err := os.WriteFile(filePath, make([]byte, 1<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	data, unmap, err := mapFile(file, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unmap()
+	if err := os.Truncate(filePath, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := countMapped(data, options{raw: true}); err == nil {
+		t.Error("Expecting an error for a file truncated while mapped")
+	}
+}
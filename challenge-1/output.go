@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// notCounted marks a count left blank, such as the distinct items of a
+// subtotal, which do not add up from the rows it covers.
+const notCounted = -1
+
+// withoutDistinct returns the counts of a row that groups several inputs,
+// with its distinct items left blank.
+func withoutDistinct(result wc.Counts) wc.Counts {
+	result.DistinctLines, result.DistinctWords = notCounted, notCounted
+	return result
+}
+
+// column is one selectable metric, in the order wc prints them.
+type column struct {
+	name  string
+	value func(wc.Counts) int
+}
+
+func selectedColumns(opts options) []column {
+	var columns []column
+	if opts.lines {
+		columns = append(columns, column{"lines", func(c wc.Counts) int { return c.Lines }})
+	}
+	if opts.words {
+		columns = append(columns, column{"words", func(c wc.Counts) int { return c.Words }})
+	}
+	if opts.chars {
+		columns = append(columns, column{"chars", func(c wc.Counts) int { return c.Chars }})
+	}
+	if opts.bytes {
+		columns = append(columns, column{"bytes", func(c wc.Counts) int { return c.Bytes }})
+	}
+	if opts.maxLineLength {
+		columns = append(columns, column{"max_line_length", func(c wc.Counts) int { return c.MaxLineLength }})
+	}
+	if opts.classify {
+		columns = append(columns,
+			column{"blank", func(c wc.Counts) int { return c.Blank }},
+			column{"comment", func(c wc.Counts) int { return c.Comment }},
+			column{"code", func(c wc.Counts) int { return c.Code }},
+		)
+	}
+	if opts.distinct != nil {
+		columns = append(columns,
+			column{"distinct_lines", func(c wc.Counts) int { return c.DistinctLines }},
+			column{"distinct_words", func(c wc.Counts) int { return c.DistinctWords }},
+		)
+	}
+	return columns
+}
+
+// reporter writes one row per input and a total row in one of the output
+// formats.
+type reporter interface {
+	report(result wc.Counts, name string) error
+	// total writes the total of the given number of inputs.
+	total(result wc.Counts, inputs int) error
+}
+
+func newReporter(w io.Writer, opts options, filePaths []string) (reporter, error) {
+	columns := selectedColumns(opts)
+	switch opts.format {
+	case formatTable:
+		return &tableReporter{w: w, columns: columns, width: numberWidth(filePaths, len(columns))}, nil
+	case formatJSON:
+		return &jsonReporter{w: w, columns: columns}, nil
+	case formatCSV:
+		return &csvReporter{w: csv.NewWriter(w), columns: columns}, nil
+	}
+	return nil, fmt.Errorf("invalid format %q, expecting table, json or csv", opts.format)
+}
+
+// tableReporter mimics wc: right aligned columns separated by a space.
+type tableReporter struct {
+	w       io.Writer
+	columns []column
+	width   int
+}
+
+func (t *tableReporter) report(result wc.Counts, name string) error {
+	var line strings.Builder
+	for i, column := range t.columns {
+		if i > 0 {
+			line.WriteByte(' ')
+		}
+		if value := column.value(result); value == notCounted {
+			fmt.Fprintf(&line, "%*s", t.width, "")
+		} else {
+			fmt.Fprintf(&line, "%*d", t.width, value)
+		}
+	}
+	if name != "" {
+		line.WriteString(" " + name)
+	}
+	line.WriteByte('\n')
+	_, err := io.WriteString(t.w, line.String())
+	return err
+}
+
+func (t *tableReporter) total(result wc.Counts, inputs int) error {
+	if inputs < 2 {
+		return nil
+	}
+	return t.report(result, "total")
+}
+
+// numberWidth picks the column width the way wc does, so that the columns
+// line up without reading the inputs twice. The width fits the total size of
+// the regular files, or 7 digits when an input has no known size. A single
+// number is never padded.
+func numberWidth(filePaths []string, columns int) int {
+	if len(filePaths) <= 1 && columns == 1 {
+		return 1
+	}
+
+	minimum := 1
+	var size int64
+	if len(filePaths) == 0 {
+		minimum = 7
+	}
+	for _, filePath := range filePaths {
+		meta, err := statInput(filePath)
+		if err != nil {
+			continue
+		}
+		if meta.Mode().IsRegular() {
+			size += meta.Size()
+		} else {
+			minimum = 7
+		}
+	}
+	return max(len(strconv.FormatInt(size, 10)), minimum)
+}
+
+// jsonReporter writes an array with one object per input and the total last.
+// Each total ends the array, so that every --follow refresh is a document of
+// its own.
+type jsonReporter struct {
+	w       io.Writer
+	columns []column
+	started bool
+}
+
+func (j *jsonReporter) report(result wc.Counts, name string) error {
+	var record strings.Builder
+	if j.started {
+		record.WriteString(",\n")
+	} else {
+		record.WriteString("[\n")
+		j.started = true
+	}
+
+	quoted, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	record.WriteString(`  {"name":` + string(quoted))
+	for _, column := range j.columns {
+		if value := column.value(result); value == notCounted {
+			fmt.Fprintf(&record, `,"%s":null`, column.name)
+		} else {
+			fmt.Fprintf(&record, `,"%s":%d`, column.name, value)
+		}
+	}
+	record.WriteString("}")
+	_, err = io.WriteString(j.w, record.String())
+	return err
+}
+
+func (j *jsonReporter) total(result wc.Counts, inputs int) error {
+	if err := j.report(result, "total"); err != nil {
+		return err
+	}
+	j.started = false
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+// csvReporter writes a header row, one row per input and the total last.
+// Each --follow refresh starts again with a header.
+type csvReporter struct {
+	w       *csv.Writer
+	columns []column
+	started bool
+}
+
+func (c *csvReporter) report(result wc.Counts, name string) error {
+	if !c.started {
+		header := []string{"name"}
+		for _, column := range c.columns {
+			header = append(header, column.name)
+		}
+		c.w.Write(header)
+		c.started = true
+	}
+
+	record := []string{name}
+	for _, column := range c.columns {
+		if value := column.value(result); value == notCounted {
+			record = append(record, "")
+		} else {
+			record = append(record, strconv.Itoa(value))
+		}
+	}
+	c.w.Write(record)
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvReporter) total(result wc.Counts, inputs int) error {
+	err := c.report(result, "total")
+	c.started = false
+	return err
+}
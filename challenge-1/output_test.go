@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+func Test_report(t *testing.T) {
+	testcases := map[string]struct {
+		opts     options
+		expected string
+	}{
+		"Should print lines and words": {
+			opts:     options{lines: true, words: true},
+			expected: "1 2 file.txt\n",
+		},
+		"Should print in canonical order": {
+			opts:     options{lines: true, words: true, chars: true, bytes: true},
+			expected: "1 2 3 4 file.txt\n",
+		},
+		"Should print the max line length last": {
+			opts:     options{lines: true, maxLineLength: true},
+			expected: "1 5 file.txt\n",
+		},
+		"Should print bytes only": {
+			opts:     options{bytes: true},
+			expected: "4 file.txt\n",
+		},
+	}
+
+	for k, v := range testcases {
+		var out strings.Builder
+		table := tableReporter{w: &out, columns: selectedColumns(v.opts), width: 1}
+		table.report(wc.Counts{Lines: 1, Words: 2, Chars: 3, Bytes: 4, MaxLineLength: 5}, "file.txt")
+		if out.String() != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", out.String())
+		}
+	}
+}
+
+func Test_reporters(t *testing.T) {
+	opts := options{lines: true, words: true, bytes: true}
+	testcases := map[string]struct {
+		format   string
+		expected string
+	}{
+		"Should align table columns": {
+			format: formatTable,
+			expected: "" +
+				"     10       2     123 a.txt\n" +
+				"   1000     200   12345 b \"c\".txt\n" +
+				"   1010     202   12468 total\n",
+		},
+		"Should write a json array": {
+			format: formatJSON,
+			expected: "[\n" +
+				`  {"name":"a.txt","lines":10,"words":2,"bytes":123},` + "\n" +
+				`  {"name":"b \"c\".txt","lines":1000,"words":200,"bytes":12345},` + "\n" +
+				`  {"name":"total","lines":1010,"words":202,"bytes":12468}` + "\n" +
+				"]\n",
+		},
+		"Should write csv with a header": {
+			format: formatCSV,
+			expected: "" +
+				"name,lines,words,bytes\n" +
+				"a.txt,10,2,123\n" +
+				"\"b \"\"c\"\".txt\",1000,200,12345\n" +
+				"total,1010,202,12468\n",
+		},
+	}
+
+	for k, v := range testcases {
+		var out strings.Builder
+		opts.format = v.format
+		r, err := newReporter(&out, opts, nil)
+		if err != nil {
+			t.Error(k, err)
+			continue
+		}
+
+		a := wc.Counts{Lines: 10, Words: 2, Bytes: 123}
+		b := wc.Counts{Lines: 1000, Words: 200, Bytes: 12345}
+		total := a
+		total.Add(b)
+		r.report(a, "a.txt")
+		r.report(b, `b "c".txt`)
+		r.total(total, 2)
+
+		if out.String() != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", out.String())
+		}
+	}
+}
+
+func Test_numberWidth(t *testing.T) {
+	testcases := map[string]struct {
+		filePaths []string
+		columns   int
+		expected  int
+	}{
+		"Should not pad a single number":          {filePaths: []string{"file.txt"}, columns: 1, expected: 1},
+		"Should fit the file size":                {filePaths: []string{"file.txt"}, columns: 3, expected: 4},
+		"Should fit the total size of all files":  {filePaths: []string{"file.txt", "file.txt", "file.txt"}, columns: 1, expected: 5},
+		"Should use 7 digits for standard input":  {filePaths: nil, columns: 3, expected: 7},
+		"Should use 7 digits for special files":   {filePaths: []string{"file.txt", "."}, columns: 1, expected: 7},
+		"Should skip files that cannot be opened": {filePaths: []string{"file.txt", "nope"}, columns: 1, expected: 4},
+	}
+
+	for k, v := range testcases {
+		if actual := numberWidth(v.filePaths, v.columns); actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_reportersRefresh(t *testing.T) {
+	opts := options{lines: true, words: true}
+	rounds := []wc.Counts{{Lines: 1, Words: 2}, {Lines: 3, Words: 5}}
+
+	var out strings.Builder
+	opts.format = formatJSON
+	r, _ := newReporter(&out, opts, nil)
+	for _, result := range rounds {
+		r.report(result, "a.txt")
+		r.total(result, 1)
+	}
+	decoder := json.NewDecoder(strings.NewReader(out.String()))
+	for i, result := range rounds {
+		var rows []map[string]any
+		if err := decoder.Decode(&rows); err != nil {
+			t.Fatal("Refresh", i, err, out.String())
+		}
+		if len(rows) != 2 || rows[0]["lines"] != float64(result.Lines) || rows[1]["name"] != "total" {
+			t.Error("Refresh", i, "Expected:", result, "Actual", rows)
+		}
+	}
+	if decoder.More() {
+		t.Error("Expected two documents, Actual", out.String())
+	}
+
+	out.Reset()
+	opts.format = formatCSV
+	r, _ = newReporter(&out, opts, nil)
+	for _, result := range rounds {
+		r.report(result, "a.txt")
+		r.total(result, 1)
+	}
+	expected := "name,lines,words\na.txt,1,2\ntotal,1,2\nname,lines,words\na.txt,3,5\ntotal,3,5\n"
+	if out.String() != expected {
+		t.Error("Expected:", expected, "Actual", out.String())
+	}
+}
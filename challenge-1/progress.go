@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+const progressInterval = 250 * time.Millisecond
+
+// meter measures how many input bytes were read, for --progress and --stats.
+// Compressed files are measured as stored, so the percentage of a file size
+// stays meaningful. A nil meter measures nothing.
+type meter struct {
+	done  atomic.Int64
+	size  int64 // total size of the inputs, or 0 when not known
+	start time.Time
+
+	stop     chan struct{}
+	finished chan struct{}
+
+	// mu guards the progress line, width being how much of it shows.
+	mu    sync.Mutex
+	w     io.Writer
+	width int
+}
+
+func newMeter(size int64) *meter {
+	return &meter{size: size, start: time.Now()}
+}
+
+// wrap returns a reader that adds what it reads to the meter.
+func (m *meter) wrap(reader io.Reader) io.Reader {
+	if m == nil {
+		return reader
+	}
+	return &meteredReader{reader: reader, done: &m.done}
+}
+
+// add adds n bytes counted without reading them.
+func (m *meter) add(n int64) {
+	if m != nil {
+		m.done.Add(n)
+	}
+}
+
+// showProgress refreshes a progress line on w until stopProgress.
+func (m *meter) showProgress(w io.Writer) {
+	m.w = w
+	m.stop = make(chan struct{})
+	m.finished = make(chan struct{})
+	go func() {
+		defer close(m.finished)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				// Clear the line, so that it does not mix with what
+				// comes next.
+				m.mu.Lock()
+				m.clearLine()
+				m.mu.Unlock()
+				return
+			case <-ticker.C:
+			}
+			line := progressLine(m.done.Load(), m.size, time.Since(m.start))
+			m.mu.Lock()
+			// Pad over the end of a longer previous line.
+			fmt.Fprintf(w, "\r%-*s", m.width, line)
+			m.width = max(m.width, len(line))
+			m.mu.Unlock()
+		}
+	}()
+}
+
+// clearLine blanks the progress line and puts the cursor back at its start.
+// The caller holds mu.
+func (m *meter) clearLine() {
+	if m.width > 0 {
+		fmt.Fprintf(m.w, "\r%s\r", strings.Repeat(" ", m.width))
+		m.width = 0
+	}
+}
+
+func (m *meter) stopProgress() {
+	if m == nil || m.stop == nil {
+		return
+	}
+	close(m.stop)
+	<-m.finished
+}
+
+// progressReporter clears the progress line before every row, so that rows
+// written to the same terminal do not land on it. The line shows again at
+// the next refresh.
+type progressReporter struct {
+	out   reporter
+	meter *meter
+}
+
+func (p progressReporter) report(result wc.Counts, name string) error {
+	p.meter.mu.Lock()
+	defer p.meter.mu.Unlock()
+	p.meter.clearLine()
+	return p.out.report(result, name)
+}
+
+func (p progressReporter) total(result wc.Counts, inputs int) error {
+	p.meter.mu.Lock()
+	defer p.meter.mu.Unlock()
+	p.meter.clearLine()
+	return p.out.total(result, inputs)
+}
+
+// progressLine shows the bytes read and the rate, with the percentage and
+// the time left when the size is known.
+func progressLine(done, size int64, elapsed time.Duration) string {
+	rate := bytesPerSecond(done, elapsed)
+	line := formatBytes(done)
+	if size > 0 {
+		line += fmt.Sprintf(" of %s %3d%%", formatBytes(size), min(done*100/size, 100))
+	}
+	line += fmt.Sprintf("  %s/s", formatBytes(int64(rate)))
+	if size > 0 && rate > 0 && done < size {
+		left := time.Duration(float64(size-done) / rate * float64(time.Second))
+		line += "  ETA " + left.Round(time.Second).String()
+	}
+	return line
+}
+
+// statsLine sums up a run for --stats.
+func statsLine(done int64, elapsed time.Duration) string {
+	return fmt.Sprintf("%s in %s, %s/s", formatBytes(done), elapsed.Round(time.Millisecond), formatBytes(int64(bytesPerSecond(done, elapsed))))
+}
+
+func bytesPerSecond(done int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(done) / elapsed.Seconds()
+}
+
+// formatBytes formats a size with decimal units, as in MB/s.
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	const prefixes = "kMGTPE"
+	value, prefix := float64(n)/unit, 0
+	for value >= unit && prefix < len(prefixes)-1 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %cB", value, prefixes[prefix])
+}
+
+// knownSize returns the total size of the inputs, or 0 when one of them is
+// not a regular file, such as stdin or a directory to walk.
+func knownSize(filePaths []string) int64 {
+	if len(filePaths) == 0 {
+		return 0
+	}
+	var size int64
+	for _, filePath := range filePaths {
+		meta, err := statInput(filePath)
+		if err != nil || !meta.Mode().IsRegular() {
+			return 0
+		}
+		size += meta.Size()
+	}
+	return size
+}
+
+type meteredReader struct {
+	reader io.Reader
+	done   *atomic.Int64
+}
+
+func (m *meteredReader) Read(p []byte) (int, error) {
+	n, err := m.reader.Read(p)
+	m.done.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+func Test_progressLine(t *testing.T) {
+	testcases := map[string]struct {
+		done     int64
+		size     int64
+		elapsed  time.Duration
+		expected string
+	}{
+		"Should show the percentage and time left": {
+			done: 250_000_000, size: 1_000_000_000, elapsed: 2 * time.Second,
+			expected: "250.0 MB of 1.0 GB  25%  125.0 MB/s  ETA 6s",
+		},
+		"Should only show bytes and rate for unknown sizes": {
+			done: 1500, elapsed: time.Second,
+			expected: "1.5 kB  1.5 kB/s",
+		},
+		"Should not divide by a zero elapsed time": {
+			done: 10, size: 100,
+			expected: "10 B of 100 B  10%  0 B/s",
+		},
+		"Should leave out the time left when done": {
+			done: 100, size: 100, elapsed: time.Second,
+			expected: "100 B of 100 B 100%  100 B/s",
+		},
+	}
+
+	for k, v := range testcases {
+		actual := progressLine(v.done, v.size, v.elapsed)
+		if actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_formatBytes(t *testing.T) {
+	testcases := map[int64]string{
+		0:                   "0 B",
+		999:                 "999 B",
+		1000:                "1.0 kB",
+		52_400_000_000:      "52.4 GB",
+		9223372036854775807: "9.2 EB",
+	}
+
+	for n, expected := range testcases {
+		if actual := formatBytes(n); actual != expected {
+			t.Error(n, "Expected:", expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_meter(t *testing.T) {
+	m := newMeter(0)
+	io.Copy(io.Discard, m.wrap(strings.NewReader("hello")))
+	m.add(3)
+	if actual := m.done.Load(); actual != 8 {
+		t.Error("Expected:", 8, "Actual", actual)
+	}
+
+	var none *meter
+	reader := strings.NewReader("x")
+	if none.wrap(reader) != io.Reader(reader) {
+		t.Error("Expecting a nil meter to leave the reader alone")
+	}
+	none.stopProgress()
+}
+
+func Test_progressReporter(t *testing.T) {
+	var stderr, stdout strings.Builder
+	m := newMeter(0)
+	m.w, m.width = &stderr, 5
+	opts := options{lines: true}
+	out := progressReporter{out: &tableReporter{w: &stdout, columns: selectedColumns(opts), width: 1}, meter: m}
+
+	out.report(wc.Counts{Lines: 1}, "a.txt")
+	if expected := "\r     \r"; stderr.String() != expected {
+		t.Errorf("Expected: %q Actual %q", expected, stderr.String())
+	}
+	out.report(wc.Counts{Lines: 2}, "b.txt")
+	if expected := "\r     \r"; stderr.String() != expected {
+		t.Errorf("Expecting a cleared line to stay cleared, Actual %q", stderr.String())
+	}
+	if expected := "1 a.txt\n2 b.txt\n"; stdout.String() != expected {
+		t.Error("Expected:", expected, "Actual", stdout.String())
+	}
+}
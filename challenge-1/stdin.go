@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+// stdinName is the operand that stands for standard input.
+const stdinName = "-"
+
+// countStdinStream counts standard input until EOF. Stdin is accepted by its
+// mode rather than its size, since pipes and terminals report a size of 0
+// no matter how much data is still to come.
+func countStdinStream(opts options) (wc.Counts, error) {
+	meta, err := os.Stdin.Stat()
+	if err != nil {
+		return wc.Counts{}, err
+	}
+	if !isStreamable(meta.Mode()) {
+		return wc.Counts{}, errors.New("No source found")
+	}
+	return countStream(opts.meter.wrap(os.Stdin), opts)
+}
+
+// isStreamable reports whether input of the given mode can be read until
+// EOF: a regular file, pipe, socket, terminal or other device.
+func isStreamable(mode fs.FileMode) bool {
+	return mode.IsRegular() || mode&(fs.ModeNamedPipe|fs.ModeSocket|fs.ModeDevice|fs.ModeCharDevice) != 0
+}
+
+// statInput stats an operand, where "-" is standard input.
+func statInput(filePath string) (fs.FileInfo, error) {
+	if filePath == stdinName {
+		return os.Stdin.Stat()
+	}
+	return os.Stat(filePath)
+}
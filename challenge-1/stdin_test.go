@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_isStreamable(t *testing.T) {
+	testcases := map[string]struct {
+		mode     fs.FileMode
+		expected bool
+	}{
+		"Should read regular files": {mode: 0644, expected: true},
+		"Should read pipes":         {mode: fs.ModeNamedPipe, expected: true},
+		"Should read sockets":       {mode: fs.ModeSocket, expected: true},
+		"Should read terminals":     {mode: fs.ModeDevice | fs.ModeCharDevice, expected: true},
+		"Should not read folders":   {mode: fs.ModeDir, expected: false},
+	}
+
+	for k, v := range testcases {
+		if actual := isStreamable(v.mode); actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_countFileFromSlowPipe(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() {
+		os.Stdin = stdin
+		reader.Close()
+	}()
+
+	go func() {
+		defer writer.Close()
+		for i := 0; i < 3; i++ {
+			time.Sleep(10 * time.Millisecond)
+			writer.WriteString("one two\n")
+		}
+	}()
+
+	actual, err := countFile(stdinName, options{})
+	if err != nil {
+		t.Error(err)
+	}
+	if actual.Lines != 3 || actual.Words != 6 || actual.Bytes != 24 {
+		t.Error("Unexpected counts", actual)
+	}
+}
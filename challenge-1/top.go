@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+// The count-min sketch of --approx takes sketchDepth * sketchWidth counters,
+// 1MB, however many distinct items there are. An estimate is too high by at
+// most e/sketchWidth of all the items, with probability 1 - 1/e^sketchDepth.
+const (
+	sketchDepth = 4
+	sketchWidth = 1 << 16
+)
+
+type itemCount struct {
+	item  string
+	count int
+}
+
+// frequencyTable counts how often each item occurs.
+type frequencyTable interface {
+	add(item []byte)
+	// top returns the n most frequent items, most frequent first.
+	top(n int) []itemCount
+}
+
+// topCounter builds the frequency table of --top from the data written to
+// it, during the counting pass.
+type topCounter struct {
+	items     *itemWriter
+	table     frequencyTable
+	foldCase  bool
+	stopWords map[string]bool
+}
+
+func newTopCounter(opts options) (*topCounter, error) {
+	t := &topCounter{foldCase: opts.ignoreCase}
+	items, err := newItemWriter(opts.by, t.add)
+	if err != nil {
+		return nil, err
+	}
+	t.items = items
+
+	if opts.approx {
+		t.table = newSketchFrequencies(opts.top)
+	} else {
+		t.table = exactFrequencies{}
+	}
+
+	if opts.stopWords != "" {
+		t.stopWords, err = readStopWords(opts.stopWords, opts.ignoreCase)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *topCounter) Write(p []byte) (int, error) {
+	return t.items.Write(p)
+}
+
+// Flush ends the current input.
+func (t *topCounter) Flush() {
+	t.items.Flush()
+}
+
+func (t *topCounter) add(item []byte) {
+	if t.foldCase {
+		item = bytes.ToLower(item)
+	}
+	if t.stopWords[string(item)] {
+		return
+	}
+	t.table.add(item)
+}
+
+// readStopWords reads a file of items to leave out, one per line. Blank lines
+// and lines starting with # are skipped.
+func readStopWords(name string, foldCase bool) (map[string]bool, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stopWords := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		if foldCase {
+			word = strings.ToLower(word)
+		}
+		stopWords[word] = true
+	}
+	return stopWords, scanner.Err()
+}
+
+// sortByCount puts the most frequent items first, and equally frequent ones
+// in byte order.
+func sortByCount(counts []itemCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].item < counts[j].item
+	})
+}
+
+// exactFrequencies counts every distinct item, in memory.
+type exactFrequencies map[string]int
+
+func (e exactFrequencies) add(item []byte) {
+	e[string(item)]++
+}
+
+func (e exactFrequencies) top(n int) []itemCount {
+	counts := make([]itemCount, 0, len(e))
+	for item, count := range e {
+		counts = append(counts, itemCount{item, count})
+	}
+	sortByCount(counts)
+	return counts[:min(n, len(counts))]
+}
+
+// sketchFrequencies estimates the counts with a count-min sketch and keeps
+// the n items with the highest estimates as candidates, so its memory does
+// not grow with the number of distinct items.
+type sketchFrequencies struct {
+	rows       [sketchDepth][]uint32
+	seed       maphash.Seed
+	n          int
+	candidates candidateHeap
+	index      map[string]*candidate
+}
+
+func newSketchFrequencies(n int) *sketchFrequencies {
+	s := &sketchFrequencies{seed: maphash.MakeSeed(), n: n, index: map[string]*candidate{}}
+	for i := range s.rows {
+		s.rows[i] = make([]uint32, sketchWidth)
+	}
+	return s
+}
+
+func (s *sketchFrequencies) add(item []byte) {
+	// Every row hashes differently, derived from one hash as h1 + i*h2.
+	hash := maphash.Bytes(s.seed, item)
+	h1, h2 := uint32(hash), uint32(hash>>32)
+	estimate := uint32(0)
+	for i := range s.rows {
+		cell := &s.rows[i][(h1+uint32(i)*h2)%sketchWidth]
+		*cell++
+		if i == 0 || *cell < estimate {
+			estimate = *cell
+		}
+	}
+
+	if c, ok := s.index[string(item)]; ok {
+		c.count = int(estimate)
+		heap.Fix(&s.candidates, c.position)
+		return
+	}
+	if len(s.candidates) < s.n {
+		c := &candidate{itemCount: itemCount{string(item), int(estimate)}}
+		heap.Push(&s.candidates, c)
+		s.index[c.item] = c
+		return
+	}
+	if len(s.candidates) > 0 && int(estimate) > s.candidates[0].count {
+		// Replace the least frequent candidate.
+		least := s.candidates[0]
+		delete(s.index, least.item)
+		least.itemCount = itemCount{string(item), int(estimate)}
+		s.index[least.item] = least
+		heap.Fix(&s.candidates, 0)
+	}
+}
+
+func (s *sketchFrequencies) top(n int) []itemCount {
+	counts := make([]itemCount, 0, len(s.candidates))
+	for _, c := range s.candidates {
+		counts = append(counts, c.itemCount)
+	}
+	sortByCount(counts)
+	return counts[:min(n, len(counts))]
+}
+
+type candidate struct {
+	itemCount
+	position int
+}
+
+// candidateHeap is a min-heap of candidates by count.
+type candidateHeap []*candidate
+
+func (h candidateHeap) Len() int           { return len(h) }
+func (h candidateHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h candidateHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].position = i
+	h[j].position = j
+}
+
+func (h *candidateHeap) Push(x any) {
+	c := x.(*candidate)
+	c.position = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *candidateHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// writeTop writes the items with their counts in the output format: like
+// uniq -c for a table, or with item and count fields for json and csv.
+func writeTop(w io.Writer, format string, counts []itemCount) error {
+	switch format {
+	case formatJSON:
+		var out strings.Builder
+		out.WriteString("[")
+		for i, c := range counts {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			quoted, err := json.Marshal(c.item)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&out, "\n  {\"item\":%s,\"count\":%d}", quoted, c.count)
+		}
+		out.WriteString("\n]\n")
+		_, err := io.WriteString(w, out.String())
+		return err
+	case formatCSV:
+		records := csv.NewWriter(w)
+		records.Write([]string{"item", "count"})
+		for _, c := range counts {
+			records.Write([]string{c.item, strconv.Itoa(c.count)})
+		}
+		records.Flush()
+		return records.Error()
+	}
+
+	var out strings.Builder
+	for _, c := range counts {
+		fmt.Fprintf(&out, "%7d %s\n", c.count, c.item)
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// discardReporter drops the rows, for --top, which prints the frequency table
+// instead.
+type discardReporter struct{}
+
+func (discardReporter) report(result wc.Counts, name string) error { return nil }
+func (discardReporter) total(result wc.Counts, inputs int) error   { return nil }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_itemWriter(t *testing.T) {
+	testcases := map[string]struct {
+		by       string
+		input    string
+		expected []string
+	}{
+		"Should split words on unicode spaces": {by: byWord, input: "one two three\n\nfour", expected: []string{"one", "two", "three", "four"}},
+		"Should keep empty lines":              {by: byLine, input: "a\n\nb", expected: []string{"a", "", "b"}},
+		"Should split characters but newlines": {by: byChar, input: "aé\nb", expected: []string{"a", "é", "b"}},
+		"Should split invalid utf-8 bytes":     {by: byChar, input: "a\xe2\x82b€\xe2", expected: []string{"a", "\xe2", "\x82", "b", "€", "\xe2"}},
+		"Should keep long words whole":         {by: byWord, input: strings.Repeat("é", 100) + " x", expected: []string{strings.Repeat("é", 100), "x"}},
+	}
+
+	for k, v := range testcases {
+		// Write in pieces of every size, to split runes across writes.
+		for size := 1; size <= len(v.input); size++ {
+			var actual []string
+			items, err := newItemWriter(v.by, func(item []byte) { actual = append(actual, string(item)) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			for start := 0; start < len(v.input); start += size {
+				items.Write([]byte(v.input[start:min(start+size, len(v.input))]))
+			}
+			items.Flush()
+			if !reflect.DeepEqual(actual, v.expected) {
+				t.Error(k, "Write size", size, "Expected:", v.expected, "Actual", actual)
+			}
+		}
+	}
+
+	if _, err := newItemWriter("sentence", nil); err == nil {
+		t.Error("Expecting error for an unknown item")
+	}
+}
+
+func Test_topCounter(t *testing.T) {
+	stopWords := filepath.Join(t.TempDir(), "stop.txt")
+	if err := os.WriteFile(stopWords, []byte("# articles\nthe\nA\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := "The cat and the dog. A cat, the end\n"
+	testcases := map[string]struct {
+		opts     options
+		expected []itemCount
+	}{
+		"Should count words exactly": {
+			opts:     options{top: 2, by: byWord},
+			expected: []itemCount{{"the", 2}, {"A", 1}},
+		},
+		"Should fold case": {
+			opts:     options{top: 2, by: byWord, ignoreCase: true},
+			expected: []itemCount{{"the", 3}, {"a", 1}},
+		},
+		"Should skip stop words": {
+			opts:     options{top: 2, by: byWord, ignoreCase: true, stopWords: stopWords},
+			expected: []itemCount{{"and", 1}, {"cat", 1}},
+		},
+		"Should estimate with a sketch": {
+			opts:     options{top: 1, by: byWord, ignoreCase: true, approx: true},
+			expected: []itemCount{{"the", 3}},
+		},
+	}
+
+	for k, v := range testcases {
+		top, err := newTopCounter(v.opts)
+		if err != nil {
+			t.Fatal(k, err)
+		}
+		top.Write([]byte(input))
+		top.Flush()
+		actual := top.table.top(v.opts.top)
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_sketchFrequencies(t *testing.T) {
+	// Many rare items must not push the frequent ones out of the candidates.
+	sketch := newSketchFrequencies(3)
+	exact := exactFrequencies{}
+	for i := 0; i < 50000; i++ {
+		items := []string{fmt.Sprint("rare", i)}
+		if i%10 == 0 {
+			items = append(items, "first")
+		}
+		if i%20 == 0 {
+			items = append(items, "second")
+		}
+		if i%40 == 0 {
+			items = append(items, "third")
+		}
+		for _, item := range items {
+			sketch.add([]byte(item))
+			exact.add([]byte(item))
+		}
+	}
+
+	actual := sketch.top(3)
+	for i, expected := range exact.top(3) {
+		if actual[i].item != expected.item || actual[i].count < expected.count {
+			t.Error("Expected:", expected, "Actual", actual[i])
+		}
+	}
+}
+
+func Test_writeTop(t *testing.T) {
+	counts := []itemCount{{"the", 12}, {`"a"`, 3}}
+	testcases := map[string]struct {
+		format   string
+		expected string
+	}{
+		"Should write like uniq -c": {format: formatTable, expected: "     12 the\n      3 \"a\"\n"},
+		"Should write a json array": {format: formatJSON, expected: "[\n  {\"item\":\"the\",\"count\":12},\n  {\"item\":\"\\\"a\\\"\",\"count\":3}\n]\n"},
+		"Should write csv":          {format: formatCSV, expected: "item,count\nthe,12\n\"\"\"a\"\"\",3\n"},
+	}
+
+	for k, v := range testcases {
+		var out strings.Builder
+		if err := writeTop(&out, v.format, counts); err != nil {
+			t.Error(k, err)
+		}
+		if out.String() != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", out.String())
+		}
+	}
+}
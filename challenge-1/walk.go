@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jawahars16/john-crickett-coding-challenges/challenge-1/wc"
+)
+
+// binarySniffSize is how much of a file is checked for a NUL byte, the same
+// heuristic git uses to tell binary files from text.
+const binarySniffSize = 8000
+
+// globList collects the values of a flag given several times.
+type globList []string
+
+func (g *globList) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *globList) Set(value string) error {
+	if _, err := path.Match(value, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", value, err)
+	}
+	*g = append(*g, value)
+	return nil
+}
+
+// walker counts the text files under a directory for -r. Each file gets a
+// row and each directory a subtotal row, named with a trailing slash, once
+// all of its files were counted. Subtotals are left out of --by-ext, and
+// leave the distinct items blank.
+type walker struct {
+	opts   options
+	out    reporter
+	errs   io.Writer
+	files  int
+	failed bool
+}
+
+// walk counts the files under root and returns their total.
+func (w *walker) walk(root string) wc.Counts {
+	return w.walkDir(root, "", nil)
+}
+
+// walkDir counts the directory dir, found at rel from the walk root, with
+// the .gitignore rules of the directories above it.
+func (w *walker) walkDir(dir, rel string, rules []ignoreRules) wc.Counts {
+	var subtotal wc.Counts
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.fail(err)
+		return subtotal
+	}
+
+	if ignoreFile, err := os.Open(filepath.Join(dir, ".gitignore")); err == nil {
+		parsed, err := parseGitignore(ignoreFile, rel)
+		ignoreFile.Close()
+		if err != nil {
+			w.fail(err)
+		} else {
+			rules = append(rules[:len(rules):len(rules)], parsed)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		w.fail(err)
+	}
+
+	counted := false
+	for _, entry := range entries {
+		name := entry.Name()
+		entryPath := filepath.Join(dir, name)
+		entryRel := path.Join(rel, name)
+
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// Symlinks to files are counted, symlinks to directories are
+			// not followed, so that a walk cannot loop.
+			meta, err := os.Stat(entryPath)
+			if err != nil || meta.IsDir() {
+				continue
+			}
+		}
+
+		if isDir && name == ".git" {
+			continue
+		}
+		if isIgnored(rules, entryRel, isDir) || matchesAny(w.opts.exclude, entryRel) {
+			continue
+		}
+		if isDir {
+			before := w.files
+			sub := w.walkDir(entryPath, entryRel, rules)
+			subtotal.Add(sub)
+			counted = counted || w.files > before
+			continue
+		}
+
+		if len(w.opts.include) > 0 && !matchesAny(w.opts.include, entryRel) {
+			continue
+		}
+		if !entry.Type().IsRegular() && entry.Type()&fs.ModeSymlink == 0 {
+			continue
+		}
+		binary, err := isBinaryFile(entryPath)
+		if err != nil {
+			w.fail(err)
+			continue
+		}
+		if binary {
+			continue
+		}
+
+		result, err := countFile(entryPath, w.opts)
+		w.files++
+		if err == nil {
+			err = w.out.report(result, entryPath)
+		}
+		w.opts.distinct.keep(err == nil)
+		if err != nil {
+			w.fail(err)
+			continue
+		}
+		subtotal.Add(result)
+		counted = true
+	}
+
+	if counted && !w.opts.byExt {
+		name := strings.TrimSuffix(dir, string(filepath.Separator)) + string(filepath.Separator)
+		if err := w.out.report(withoutDistinct(subtotal), name); err != nil {
+			w.fail(err)
+		}
+	}
+	return subtotal
+}
+
+func (w *walker) fail(err error) {
+	fmt.Fprintln(w.errs, err)
+	w.failed = true
+}
+
+// matchesAny reports whether a pattern matches the path, relative to the
+// walk root. A pattern without a slash is matched against the base name, so
+// that *.go matches Go files at any depth.
+func matchesAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		name := rel
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinaryFile reports whether the start of the file holds a NUL byte.
+func isBinaryFile(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, binarySniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return bytes.IndexByte(head[:n], 0) >= 0, nil
+}
+
+func isDirectory(filePath string) bool {
+	meta, err := os.Stat(filePath)
+	return err == nil && meta.IsDir()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_walker(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":         "vendor/\n*.log\n",
+		"a.go":               "a b\nc\n",
+		"debug.log":          "ignored\n",
+		"blob.go":            "bin\x00ary\n",
+		"src/b.go":           "x\n",
+		"src/notes.md":       "one two three\n",
+		"src/deep/c.go":      "y\nz\n",
+		"vendor/v.go":        "vendored\n",
+		".git/HEAD":          "ref\n",
+		"empty/only.log":     "ignored\n",
+		"src/deep/skip.go":   "skipped\n",
+		"src/deep/skip_test": "skipped\n",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testcases := map[string]struct {
+		include  globList
+		exclude  globList
+		expected []string
+	}{
+		"Should count text files with subtotals": {
+			exclude: globList{"skip*"},
+			expected: []string{
+				"2 .gitignore",
+				"2 a.go",
+				"1 src/b.go",
+				"2 src/deep/c.go",
+				"2 src/deep/",
+				"1 src/notes.md",
+				"4 src/",
+				"8 ",
+			},
+		},
+		"Should only count included files": {
+			include: globList{"*.go"},
+			exclude: globList{"src/deep/skip.go", "skip_test"},
+			expected: []string{
+				"2 a.go",
+				"1 src/b.go",
+				"2 src/deep/c.go",
+				"2 src/deep/",
+				"3 src/",
+				"5 ",
+			},
+		},
+		"Should skip excluded directories": {
+			include: globList{"*.go"},
+			exclude: globList{"deep"},
+			expected: []string{
+				"2 a.go",
+				"1 src/b.go",
+				"1 src/",
+				"3 ",
+			},
+		},
+	}
+
+	for k, v := range testcases {
+		var out strings.Builder
+		opts := options{lines: true, include: v.include, exclude: v.exclude}
+		table := &tableReporter{w: &out, columns: selectedColumns(opts), width: 1}
+		var errs strings.Builder
+		w := walker{opts: opts, out: table, errs: &errs}
+		w.walk(root + string(filepath.Separator))
+
+		actual := strings.ReplaceAll(out.String(), root+string(filepath.Separator), "")
+		expected := strings.Join(v.expected, "\n") + "\n"
+		if actual != expected || w.failed {
+			t.Error(k, "Expected:", expected, "Actual", actual, errs.String())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package wc
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// Language describes the comment and string syntax of a programming language,
+// enough to tell comment lines from code lines.
+type Language struct {
+	Name string
+	// LineComments start a comment that runs to the end of the line.
+	LineComments []string
+	// BlockComments are pairs of start and end markers.
+	BlockComments [][2]string
+	// Strings are matched in order, so longer delimiters sharing a prefix
+	// with shorter ones come first. Comment markers in strings are code.
+	Strings []StringSyntax
+	// CommentAfterSpace only starts line comments at the start of a line or
+	// after white space, as a shell does with #.
+	CommentAfterSpace bool
+}
+
+// StringSyntax describes one kind of string literal.
+type StringSyntax struct {
+	Delimiter string
+	// Escapes is set when a backslash escapes the next byte.
+	Escapes bool
+	// Multiline is set when the string may span lines. Any other string
+	// ends with its line, so that a stray quote cannot swallow the file.
+	Multiline bool
+}
+
+var (
+	goLanguage = &Language{
+		Name:          "Go",
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []StringSyntax{
+			{Delimiter: `"`, Escapes: true},
+			{Delimiter: "'", Escapes: true},
+			{Delimiter: "`", Multiline: true},
+		},
+	}
+	pythonLanguage = &Language{
+		Name:         "Python",
+		LineComments: []string{"#"},
+		Strings: []StringSyntax{
+			{Delimiter: `"""`, Escapes: true, Multiline: true},
+			{Delimiter: "'''", Escapes: true, Multiline: true},
+			{Delimiter: `"`, Escapes: true},
+			{Delimiter: "'", Escapes: true},
+		},
+	}
+	javaScriptLanguage = &Language{
+		Name:          "JavaScript",
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []StringSyntax{
+			{Delimiter: `"`, Escapes: true},
+			{Delimiter: "'", Escapes: true},
+			{Delimiter: "`", Escapes: true, Multiline: true},
+		},
+	}
+	shellLanguage = &Language{
+		Name:         "Shell",
+		LineComments: []string{"#"},
+		Strings: []StringSyntax{
+			{Delimiter: `"`, Escapes: true, Multiline: true},
+			{Delimiter: "'", Multiline: true},
+		},
+		CommentAfterSpace: true,
+	}
+	jsonLanguage = &Language{
+		Name:    "JSON",
+		Strings: []StringSyntax{{Delimiter: `"`, Escapes: true}},
+	}
+)
+
+var languagesByExtension = map[string]*Language{
+	".go":   goLanguage,
+	".py":   pythonLanguage,
+	".js":   javaScriptLanguage,
+	".mjs":  javaScriptLanguage,
+	".cjs":  javaScriptLanguage,
+	".jsx":  javaScriptLanguage,
+	".sh":   shellLanguage,
+	".bash": shellLanguage,
+	".json": jsonLanguage,
+}
+
+// LanguageFor returns the language of a file by its extension, or nil when
+// it is not a known one.
+func LanguageFor(fileName string) *Language {
+	return languagesByExtension[strings.ToLower(filepath.Ext(fileName))]
+}
+
+type classifierState int
+
+const (
+	inCode classifierState = iota
+	inLineComment
+	inBlockComment
+	inString
+)
+
+// lineClassifier sorts lines into blank, comment and code lines. A line with
+// anything but comments and white space is code, even with a comment at its
+// end; a line with only white space is blank, even inside a block comment.
+// Delimiters are ASCII, so UTF-8 text needs no decoding.
+type lineClassifier struct {
+	lang    *Language
+	longest int    // length of the longest delimiter
+	pending []byte // a possible delimiter cut by the end of a write
+
+	state   classifierState
+	open    int // index of the block comment or string that is open
+	escaped bool
+
+	lineStarted bool
+	afterSpace  bool
+	hasCode     bool
+	hasComment  bool
+
+	blank   int
+	comment int
+	code    int
+}
+
+func newLineClassifier(lang *Language) *lineClassifier {
+	l := &lineClassifier{lang: lang, afterSpace: true}
+	for _, marker := range lang.LineComments {
+		l.longest = max(l.longest, len(marker))
+	}
+	for _, markers := range lang.BlockComments {
+		l.longest = max(l.longest, len(markers[0]), len(markers[1]))
+	}
+	for _, syntax := range lang.Strings {
+		l.longest = max(l.longest, len(syntax.Delimiter))
+	}
+	return l
+}
+
+func (l *lineClassifier) write(p []byte) {
+	data := p
+	if len(l.pending) > 0 {
+		// Finish the delimiter cut by the previous write with the start of
+		// p, rather than copy all of p after it.
+		carried := len(l.pending)
+		joined := append(l.pending, p[:min(len(p), l.longest)]...)
+		l.pending = nil
+		if len(p) <= l.longest {
+			l.scan(joined, false)
+			return
+		}
+		// joined holds a whole delimiter from any of the carried bytes, so
+		// they step as they would in one write.
+		i := 0
+		for i < carried {
+			i += l.step(joined[i:])
+		}
+		data = p[i-carried:]
+	}
+	l.scan(data, false)
+}
+
+// scan classifies data. Unless final, bytes at its end that may be the start
+// of a delimiter are kept until the next write.
+func (l *lineClassifier) scan(data []byte, final bool) {
+	for i := 0; i < len(data); {
+		if !final && len(data)-i < l.longest && data[i] != '\n' {
+			l.pending = append([]byte(nil), data[i:]...)
+			return
+		}
+		i += l.step(data[i:])
+	}
+}
+
+// step classifies the first bytes of rest and returns how many it used.
+func (l *lineClassifier) step(rest []byte) int {
+	b := rest[0]
+	if b == '\n' {
+		l.endLine()
+		return 1
+	}
+	l.lineStarted = true
+	space := isSpaceByte(b)
+
+	switch l.state {
+	case inLineComment:
+		l.hasComment = l.hasComment || !space
+		return 1
+	case inBlockComment:
+		end := l.lang.BlockComments[l.open][1]
+		if bytes.HasPrefix(rest, []byte(end)) {
+			l.state = inCode
+			l.hasComment = true
+			return len(end)
+		}
+		l.hasComment = l.hasComment || !space
+		return 1
+	case inString:
+		syntax := l.lang.Strings[l.open]
+		l.hasCode = true
+		switch {
+		case l.escaped:
+			l.escaped = false
+		case syntax.Escapes && b == '\\':
+			l.escaped = true
+		case bytes.HasPrefix(rest, []byte(syntax.Delimiter)):
+			l.state = inCode
+			return len(syntax.Delimiter)
+		}
+		return 1
+	}
+
+	if space {
+		l.afterSpace = true
+		return 1
+	}
+	for _, marker := range l.lang.LineComments {
+		if bytes.HasPrefix(rest, []byte(marker)) && (l.afterSpace || !l.lang.CommentAfterSpace) {
+			l.state = inLineComment
+			l.hasComment = true
+			return len(marker)
+		}
+	}
+	for i, markers := range l.lang.BlockComments {
+		if bytes.HasPrefix(rest, []byte(markers[0])) {
+			l.state, l.open = inBlockComment, i
+			l.hasComment = true
+			return len(markers[0])
+		}
+	}
+	l.hasCode = true
+	l.afterSpace = false
+	for i, syntax := range l.lang.Strings {
+		if bytes.HasPrefix(rest, []byte(syntax.Delimiter)) {
+			l.state, l.open = inString, i
+			return len(syntax.Delimiter)
+		}
+	}
+	return 1
+}
+
+func (l *lineClassifier) endLine() {
+	switch {
+	case l.hasCode:
+		l.code++
+	case l.hasComment:
+		l.comment++
+	default:
+		l.blank++
+	}
+
+	if l.state == inLineComment || l.state == inString && !l.lang.Strings[l.open].Multiline {
+		l.state = inCode
+	}
+	l.escaped = false
+	l.lineStarted = false
+	l.afterSpace = true
+	l.hasComment = false
+	// The lines of a multiline string are code, even empty ones.
+	l.hasCode = l.state == inString
+}
+
+// counts returns the blank, comment and code lines so far, finishing a last
+// line that has no newline, without changing l.
+func (l *lineClassifier) counts() (blank, comment, code int) {
+	last := *l
+	last.pending = nil
+	last.scan(l.pending, true)
+	if last.lineStarted {
+		last.endLine()
+	}
+	return last.blank, last.comment, last.code
+}
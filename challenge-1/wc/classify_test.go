@@ -0,0 +1,101 @@
+package wc
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_classifyLines(t *testing.T) {
+	type lines struct{ blank, comment, code int }
+	testcases := map[string]struct {
+		file     string
+		input    string
+		expected lines
+	}{
+		"Should split go lines": {
+			file:     "main.go",
+			input:    "package main\n\n// Doc comment.\nfunc main() {} // trailing\n",
+			expected: lines{blank: 1, comment: 1, code: 2},
+		},
+		"Should span go block comments": {
+			file:     "main.go",
+			input:    "/*\n   block\n\n*/\nx := 1 /* inline */\n/* a */ y := 2\n",
+			expected: lines{blank: 1, comment: 3, code: 2},
+		},
+		"Should ignore comment markers in strings": {
+			file:     "main.go",
+			input:    "s := \"// not a comment\"\nr := '\\''// comment\nt := \"\\\"/*\"\n",
+			expected: lines{code: 3},
+		},
+		"Should count raw string lines as code": {
+			file:     "main.go",
+			input:    "s := `\n\n// text\n`\n",
+			expected: lines{code: 4},
+		},
+		"Should count python docstrings as code": {
+			file:     "a.py",
+			input:    "# comment\ndef f():\n    \"\"\"Doc\n    # text\n    \"\"\"\n    return '#'\n",
+			expected: lines{comment: 1, code: 5},
+		},
+		"Should span javascript template literals": {
+			file:     "a.js",
+			input:    "const s = `a\n// b`\n/** doc\n * more */\n",
+			expected: lines{comment: 2, code: 2},
+		},
+		"Should only start shell comments after space": {
+			file:     "run.sh",
+			input:    "#!/bin/sh\necho ${#x} # count\necho 'it''s' a#b\n\n",
+			expected: lines{blank: 1, comment: 1, code: 2},
+		},
+		"Should count json lines as code": {
+			file:     "a.JSON",
+			input:    "{\n  \"url\": \"http://x/*\"\n\n}",
+			expected: lines{blank: 1, code: 3},
+		},
+		"Should end a broken string with its line": {
+			file:     "main.go",
+			input:    "s := \"open\n// comment\n",
+			expected: lines{comment: 1, code: 1},
+		},
+	}
+
+	for k, v := range testcases {
+		actual, err := Count(strings.NewReader(v.input), Options{Language: LanguageFor(v.file)})
+		if err != nil {
+			t.Error(k, err)
+		}
+		got := lines{actual.Blank, actual.Comment, actual.Code}
+		if got != v.expected || got.blank+got.comment+got.code != actual.Lines {
+			t.Error(k, "Expected:", v.expected, "Actual", got, "of", actual.Lines, "lines")
+		}
+	}
+}
+
+func Test_classifySplitWrites(t *testing.T) {
+	inputs := map[string]string{
+		"a.go": "x := 1 /* a\n*/ // b\n\"/*\"\n",
+		"a.py": "x = '''a\n# b\n''' # c\n\"\"\"\"\"\"\n# d\n",
+	}
+	for file, input := range inputs {
+		opts := Options{Language: LanguageFor(file)}
+		expected, _ := Count(strings.NewReader(input), opts)
+		for size := 1; size < len(input); size++ {
+			counter, _ := NewCounter(opts)
+			for start := 0; start < len(input); start += size {
+				counter.Write([]byte(input[start:min(start+size, len(input))]))
+			}
+			if actual := counter.Counts(); actual != expected {
+				t.Error(file, "Write size", size, "Expected:", expected, "Actual", actual)
+			}
+		}
+	}
+}
+
+func Test_LanguageFor(t *testing.T) {
+	if LanguageFor("README") != nil || LanguageFor("notes.txt") != nil {
+		t.Error("Expecting no language for unknown extensions")
+	}
+	if lang := LanguageFor("dir/app.mjs"); lang == nil || lang.Name != "JavaScript" {
+		t.Error("Expecting JavaScript for .mjs, Actual", lang)
+	}
+}
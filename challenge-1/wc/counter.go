@@ -0,0 +1,224 @@
+package wc
+
+import (
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Counter computes every metric in a single pass over the data written to
+// it. Input can be written in chunks of any size; a rune split across two
+// writes is carried over to the next one.
+type Counter struct {
+	counts   Counts
+	inWord   bool
+	lastByte byte
+	partial  []byte // incomplete rune left at the end of the previous write
+
+	// splitter splits words in place of the default Unicode white space
+	// rules, when set.
+	splitter      wordSplitter
+	charsAreBytes bool
+	classifier    *lineClassifier
+
+	// lineWidth is the display width of the current line so far. The
+	// prefix fields describe the text before the first line break, which
+	// parallel counting needs to re-measure from the column it really
+	// starts at.
+	lineWidth    int
+	sawBreak     bool
+	prefixWidth  int
+	prefixTabbed bool
+	prefixTab    int
+}
+
+// NewCounter returns a Counter with the given options. The zero Counter is
+// ready to use with the zero Options.
+func NewCounter(opts Options) (*Counter, error) {
+	splitter, err := newWordSplitter(opts.WordMode)
+	if err != nil {
+		return nil, err
+	}
+	c := &Counter{splitter: splitter, charsAreBytes: opts.CharsAreBytes}
+	if opts.Language != nil {
+		c.classifier = newLineClassifier(opts.Language)
+	}
+	return c, nil
+}
+
+// Write counts p. It never fails.
+func (c *Counter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	c.counts.Bytes += len(p)
+	c.lastByte = p[len(p)-1]
+	if c.classifier != nil {
+		c.classifier.write(p)
+	}
+
+	data := p
+	if len(c.partial) > 0 {
+		// Complete the pending rune with the first bytes of this write.
+		pending := len(c.partial)
+		head := append(c.partial, p[:min(len(p), utf8.UTFMax)]...)
+		i := 0
+		for i < pending {
+			if !utf8.FullRune(head[i:]) {
+				c.partial = append(c.partial[:0], head[i:]...)
+				return len(p), nil
+			}
+			r, width := utf8.DecodeRune(head[i:])
+			c.addDecoded(r, width)
+			i += width
+		}
+		data = p[i-pending:]
+		c.partial = c.partial[:0]
+	}
+
+	for i := 0; i < len(data); {
+		b := data[i]
+		if b < utf8.RuneSelf {
+			c.addASCII(b)
+			i++
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			c.partial = append(c.partial[:0], data[i:]...)
+			break
+		}
+		r, width := utf8.DecodeRune(data[i:])
+		c.addDecoded(r, width)
+		i += width
+	}
+	return len(p), nil
+}
+
+// ReadFrom counts everything read from reader until EOF, using one buffered
+// read loop. It implements io.ReaderFrom, so io.Copy uses it too.
+func (c *Counter) ReadFrom(reader io.Reader) (int64, error) {
+	var total int64
+	buffer := make([]byte, bufferSize)
+	for {
+		n, err := reader.Read(buffer)
+		c.Write(buffer[:n])
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Flush counts the bytes of an unfinished rune at the end of the input as
+// one invalid character each. Invalid characters have no display width.
+func (c *Counter) Flush() {
+	if len(c.partial) == 0 {
+		return
+	}
+	c.counts.Chars += len(c.partial)
+	for range c.partial {
+		c.updateWord(utf8.RuneError)
+	}
+	c.partial = c.partial[:0]
+}
+
+// Counts returns the counts of everything written so far, and can be called
+// at any time. A last line without a trailing newline still counts as a
+// line. The bytes of a rune that is not complete yet are only counted as
+// characters by Flush.
+func (c *Counter) Counts() Counts {
+	result := c.counts
+	result.MaxLineLength = max(result.MaxLineLength, c.lineWidth)
+	if result.Bytes > 0 && c.lastByte != '\n' {
+		result.Lines++
+	}
+	if c.charsAreBytes {
+		result.Chars = result.Bytes
+	}
+	if c.classifier != nil {
+		result.Blank, result.Comment, result.Code = c.classifier.counts()
+	}
+	return result
+}
+
+func (c *Counter) addASCII(b byte) {
+	c.counts.Chars++
+	switch b {
+	case '\n':
+		c.counts.Lines++
+		c.endLine()
+	case '\r', '\f':
+		c.endLine()
+	case '\t':
+		c.tab()
+	default:
+		if b >= ' ' && b < 0x7f { // printable
+			c.lineWidth++
+		}
+	}
+	c.updateWord(rune(b))
+}
+
+func (c *Counter) addDecoded(r rune, width int) {
+	c.counts.Chars++
+	if r != utf8.RuneError || width > 1 {
+		// Invalid bytes have no display width.
+		c.lineWidth += runeWidth(r)
+	}
+	c.updateWord(r)
+}
+
+// endLine finishes the current line the way wc -L does, on a newline,
+// carriage return or form feed.
+func (c *Counter) endLine() {
+	if !c.sawBreak {
+		c.sawBreak = true
+		c.prefixWidth = c.lineWidth
+	}
+	c.counts.MaxLineLength = max(c.counts.MaxLineLength, c.lineWidth)
+	c.lineWidth = 0
+}
+
+// tab moves to the next tab stop, every 8 columns.
+func (c *Counter) tab() {
+	if !c.sawBreak && !c.prefixTabbed {
+		c.prefixTabbed = true
+		c.prefixTab = c.lineWidth
+	}
+	c.lineWidth += tabWidth - c.lineWidth%tabWidth
+}
+
+func (c *Counter) updateWord(r rune) {
+	if c.splitter != nil {
+		if c.splitter.startsWord(r) {
+			c.counts.Words++
+		}
+		return
+	}
+
+	var space bool
+	if r < utf8.RuneSelf {
+		space = isSpaceByte(byte(r))
+	} else {
+		space = unicode.IsSpace(r)
+	}
+	if space {
+		c.inWord = false
+		return
+	}
+	if !c.inWord {
+		c.inWord = true
+		c.counts.Words++
+	}
+}
+
+func isSpaceByte(b byte) bool {
+	switch b {
+	case '\t', '\n', '\v', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package wc
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var benchSize = flag.Int64("benchsize", 64<<20, "Bytes of generated input for the count benchmarks")
+
+func Test_count(t *testing.T) {
+	testcases := map[string]struct {
+		expected int
+		argument string
+		input    string
+	}{
+		"Should return number of bytes": {
+			argument: "c",
+			input:    "one two three ท",
+			expected: 17,
+		},
+		"Should return number of words": {
+			argument: "w",
+			input:    "one two three",
+			expected: 3,
+		},
+		"Should return number of lines": {
+			argument: "l",
+			input:    "one two three\nfour five six",
+			expected: 2,
+		},
+		"Should return number of characters": {
+			argument: "m",
+			input:    "one two three ท",
+			expected: 15,
+		},
+	}
+
+	for k, v := range testcases {
+		var c Counter
+		c.Write([]byte(v.input))
+		c.Flush()
+		result := c.Counts()
+		actual := map[string]int{
+			"l": result.Lines,
+			"w": result.Words,
+			"m": result.Chars,
+			"c": result.Bytes,
+		}[v.argument]
+		if actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_counterSplitWrites(t *testing.T) {
+	input := []byte("one two\tthree ท\nfour five  ๏ six\n\xe2\x82 seven\xff")
+
+	var whole Counter
+	whole.Write(input)
+	whole.Flush()
+	expected := whole.Counts()
+
+	for size := 1; size <= len(input); size++ {
+		var c Counter
+		for start := 0; start < len(input); start += size {
+			c.Write(input[start:min(start+size, len(input))])
+		}
+		c.Flush()
+		if actual := c.Counts(); actual != expected {
+			t.Error("Chunk size", size, "Expected:", expected, "Actual", actual)
+		}
+	}
+
+	lines, words, chars, bytes := countFromPipes(strings.NewReader(string(input)))
+	reference := Counts{Lines: lines, Words: words, Chars: chars, Bytes: bytes}
+	expected.MaxLineLength = 0
+	if reference != expected {
+		t.Error("Expected:", reference, "Actual", expected)
+	}
+}
+
+func Test_counterMaxLineLength(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected int
+	}{
+		"Should measure the longest line":          {input: "one\nthree\ntwo\n", expected: 5},
+		"Should measure an unterminated line":      {input: "one\nthree four", expected: 10},
+		"Should expand tabs to 8 columns":          {input: "ab\tc\n\t\tx\n", expected: 17},
+		"Should count wide runes as 2 columns":     {input: "日本語\nabcde\n", expected: 6},
+		"Should not count combining marks":         {input: "e\u0301e\u0301\n", expected: 2},
+		"Should reset at carriage returns":         {input: "abcdef\rab\n", expected: 6},
+		"Should not count control characters":      {input: "a\x00b\x1bc\n", expected: 3},
+		"Should not count invalid utf-8 sequences": {input: "a\xffb\xe2\x82", expected: 2},
+	}
+
+	for k, v := range testcases {
+		var c Counter
+		c.Write([]byte(v.input))
+		c.Flush()
+		if actual := c.Counts().MaxLineLength; actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
+
+func Test_CounterCountsSoFar(t *testing.T) {
+	var c Counter
+	c.Write([]byte("one two\nthree \xe0\xb8"))
+	expected := Counts{Lines: 2, Words: 3, Chars: 14, Bytes: 16, MaxLineLength: 7}
+	if actual := c.Counts(); actual != expected {
+		t.Error("Expected:", expected, "Actual", actual)
+	}
+
+	c.Write([]byte("\x97 four\n"))
+	expected = Counts{Lines: 2, Words: 5, Chars: 21, Bytes: 23, MaxLineLength: 12}
+	if actual := c.Counts(); actual != expected {
+		t.Error("Expected:", expected, "Actual", actual)
+	}
+}
+
+func Test_CountLongLine(t *testing.T) {
+	line := strings.Repeat("word ", 100000) + "\n"
+
+	actual, err := Count(strings.NewReader(line+line), Options{})
+	if err != nil {
+		t.Error(err)
+	}
+	expected := Counts{Lines: 2, Words: 200000, Chars: 2 * len(line), Bytes: 2 * len(line), MaxLineLength: len(line) - 1}
+	if actual != expected {
+		t.Error("Expected:", expected, "Actual", actual)
+	}
+}
+
+func BenchmarkCount(b *testing.B) {
+	b.SetBytes(*benchSize)
+	for i := 0; i < b.N; i++ {
+		if _, err := Count(newSampleReader(*benchSize), Options{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountPipes(b *testing.B) {
+	b.SetBytes(*benchSize)
+	for i := 0; i < b.N; i++ {
+		countFromPipes(newSampleReader(*benchSize))
+	}
+}
+
+// sampleReader produces size bytes of repeated text without holding the
+// whole input in memory, so benchmarks can run on multi-gigabyte inputs:
+//
+//	go test -bench Count -args -benchsize=4294967296
+type sampleReader struct {
+	remaining int64
+	offset    int
+}
+
+const sampleText = "The quick brown fox jumps over the lazy dog.\tเสือ กระโดด\n"
+
+func newSampleReader(size int64) *sampleReader {
+	return &sampleReader{remaining: size}
+}
+
+func (s *sampleReader) Read(p []byte) (int, error) {
+	if s.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.remaining {
+		p = p[:s.remaining]
+	}
+	n := 0
+	for n < len(p) {
+		copied := copy(p[n:], sampleText[s.offset:])
+		n += copied
+		s.offset = (s.offset + copied) % len(sampleText)
+	}
+	s.remaining -= int64(n)
+	return n, nil
+}
+
+// countFromPipes is the previous implementation, which fans the input out to
+// one scanner per metric. It is kept as a reference for the tests and
+// benchmarks above.
+func countFromPipes(reader io.Reader) (int, int, int, int) {
+	splitFuncs := []bufio.SplitFunc{bufio.ScanLines, bufio.ScanWords, bufio.ScanRunes, bufio.ScanBytes}
+	results := make([]int, len(splitFuncs))
+	writers := make([]io.Writer, len(splitFuncs))
+
+	var wg sync.WaitGroup
+	for i, splitFunc := range splitFuncs {
+		pr, pw := io.Pipe()
+		writers[i] = pw
+		wg.Add(1)
+		go func(i int, splitFunc bufio.SplitFunc) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(pr)
+			scanner.Split(splitFunc)
+			for scanner.Scan() {
+				results[i]++
+			}
+			pr.CloseWithError(scanner.Err())
+		}(i, splitFunc)
+	}
+
+	io.Copy(io.MultiWriter(writers...), reader)
+	for _, writer := range writers {
+		writer.(*io.PipeWriter).Close()
+	}
+	wg.Wait()
+	return results[0], results[1], results[2], results[3]
+}
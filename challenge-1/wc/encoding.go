@@ -0,0 +1,93 @@
+package wc
+
+import (
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// utf16ByteOrder returns the byte order of a UTF-16 byte order mark at the
+// start of data, or nil when there is none.
+func utf16ByteOrder(data []byte) binary.ByteOrder {
+	switch {
+	case len(data) < 2:
+		return nil
+	case data[0] == 0xff && data[1] == 0xfe:
+		return binary.LittleEndian
+	case data[0] == 0xfe && data[1] == 0xff:
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// utf16Writer decodes UTF-16 and writes it to w as UTF-8. Unpaired
+// surrogates and a trailing odd byte are written as utf8.RuneError.
+type utf16Writer struct {
+	w     io.Writer
+	order binary.ByteOrder
+	bytes int // UTF-16 bytes written so far
+
+	odd     []byte
+	high    uint16 // high surrogate waiting on its pair
+	decoded []byte
+}
+
+func (u *utf16Writer) Write(p []byte) (int, error) {
+	u.bytes += len(p)
+	data := p
+	if len(u.odd) > 0 {
+		data = append(u.odd, p...)
+		u.odd = nil
+	}
+
+	u.decoded = u.decoded[:0]
+	for ; len(data) >= 2; data = data[2:] {
+		u.decode(u.order.Uint16(data))
+	}
+	if len(data) == 1 {
+		u.odd = []byte{data[0]}
+	}
+
+	if _, err := u.w.Write(u.decoded); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (u *utf16Writer) decode(unit uint16) {
+	if u.high != 0 {
+		high := u.high
+		u.high = 0
+		if r := utf16.DecodeRune(rune(high), rune(unit)); r != utf8.RuneError {
+			u.decoded = utf8.AppendRune(u.decoded, r)
+			return
+		}
+		u.decoded = utf8.AppendRune(u.decoded, utf8.RuneError)
+	}
+
+	switch {
+	case 0xd800 <= unit && unit < 0xdc00:
+		u.high = unit
+	case 0xdc00 <= unit && unit < 0xe000:
+		u.decoded = utf8.AppendRune(u.decoded, utf8.RuneError)
+	default:
+		u.decoded = utf8.AppendRune(u.decoded, rune(unit))
+	}
+}
+
+// flush writes whatever is left of an unfinished code unit or surrogate
+// pair as an invalid character.
+func (u *utf16Writer) flush() error {
+	u.decoded = u.decoded[:0]
+	if u.high != 0 {
+		u.decoded = utf8.AppendRune(u.decoded, utf8.RuneError)
+		u.high = 0
+	}
+	if len(u.odd) > 0 {
+		u.decoded = utf8.AppendRune(u.decoded, utf8.RuneError)
+		u.odd = nil
+	}
+	_, err := u.w.Write(u.decoded)
+	return err
+}
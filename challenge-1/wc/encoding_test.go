@@ -0,0 +1,46 @@
+package wc
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_CountEncodings(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		opts     Options
+		expected Counts
+	}{
+		"Should count bytes as chars in the C locale": {
+			input:    "one ท\n",
+			opts:     Options{CharsAreBytes: true},
+			expected: Counts{Lines: 1, Words: 2, Chars: 8, Bytes: 8, MaxLineLength: 5},
+		},
+		"Should decode UTF-16 little endian": {
+			input:    "\xff\xfeo\x00n\x00e\x00 \x00\x17\x0e\n\x00",
+			expected: Counts{Lines: 1, Words: 2, Chars: 6, Bytes: 14, MaxLineLength: 5},
+		},
+		"Should decode UTF-16 big endian with surrogate pairs": {
+			input:    "\xfe\xff\x00a\xd8\x3d\xde\x00\x00\n",
+			expected: Counts{Lines: 1, Words: 1, Chars: 3, Bytes: 10, MaxLineLength: 3},
+		},
+		"Should count lone surrogates and odd bytes as invalid": {
+			input:    "\xff\xfe\x00\xd8a\x00b",
+			expected: Counts{Lines: 1, Words: 1, Chars: 3, Bytes: 7, MaxLineLength: 3},
+		},
+		"Should not count the byte order mark as text": {
+			input:    "\xff\xfe \x00h\x00i\x00",
+			expected: Counts{Lines: 1, Words: 1, Chars: 3, Bytes: 8, MaxLineLength: 3},
+		},
+	}
+
+	for k, v := range testcases {
+		actual, err := Count(strings.NewReader(v.input), v.opts)
+		if err != nil {
+			t.Error(k, err)
+		}
+		if actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual)
+		}
+	}
+}
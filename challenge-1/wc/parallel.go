@@ -0,0 +1,195 @@
+package wc
+
+import (
+	"io"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MinChunkSize is the smallest chunk worth counting on its own; smaller
+// chunks cost more to schedule than to count.
+const MinChunkSize = 4 * 1024 * 1024
+
+// chunkResult is the outcome of counting one chunk, along with what the merge
+// needs to know about the chunk edges.
+type chunkResult struct {
+	Counts
+	startsInWord bool
+	endsInWord   bool
+	lastByte     byte
+	err          error
+
+	// The line widths at the chunk edges, see linePrefix.
+	prefix    linePrefix
+	hasBreak  bool
+	lineWidth int
+}
+
+// linePrefix describes the text before the first line break of a chunk.
+// Only the tab stops make its width depend on the column it starts at, and
+// after the first tab the line is aligned to a tab stop regardless.
+type linePrefix struct {
+	width  int // measured from column 0
+	tabbed bool
+	tab    int // column of the first tab, measured from column 0
+}
+
+// endColumn returns the column the prefix ends at when it starts at start.
+func (p linePrefix) endColumn(start int) int {
+	if !p.tabbed {
+		return start + p.width
+	}
+	stop := func(column int) int {
+		return column + tabWidth - column%tabWidth
+	}
+	return stop(start+p.tab) + p.width - stop(p.tab)
+}
+
+// CountParallel splits the input into the given number of chunks and counts
+// them concurrently, with the same result as Count. Chunk edges are moved to
+// rune boundaries so no rune is split, and a word that straddles an edge is
+// counted once. Check CanCountParallel first.
+func CountParallel(reader io.ReaderAt, size int64, chunks int, opts Options) (Counts, error) {
+	offsets, err := chunkOffsets(reader, size, chunks)
+	if err != nil {
+		return Counts{}, err
+	}
+
+	results := make([]chunkResult, len(offsets)-1)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = countChunk(reader, offsets[i], offsets[i+1], opts.Progress)
+		}(i)
+	}
+	wg.Wait()
+
+	var (
+		total  Counts
+		column int
+	)
+	for i, result := range results {
+		if result.err != nil {
+			return Counts{}, result.err
+		}
+		total.Add(result.Counts)
+		if i > 0 && results[i-1].endsInWord && result.startsInWord {
+			total.Words--
+		}
+
+		// Finish the line carried over from the previous chunks.
+		end := result.prefix.endColumn(column)
+		if result.hasBreak {
+			total.MaxLineLength = max(total.MaxLineLength, end)
+			column = result.lineWidth
+		} else {
+			column = end
+		}
+	}
+	total.MaxLineLength = max(total.MaxLineLength, column)
+	if opts.CharsAreBytes {
+		total.Chars = total.Bytes
+	}
+
+	// Same as the sequential counter, an unterminated last line is a line.
+	if last := results[len(results)-1]; total.Bytes > 0 && last.lastByte != '\n' {
+		total.Lines++
+	}
+	return total, nil
+}
+
+// CanCountParallel reports whether the input can be split into chunks. The
+// chunks are merged assuming words are split on Unicode white space, and
+// UTF-16 and comments have to be read from their start.
+func CanCountParallel(reader io.ReaderAt, opts Options) bool {
+	if opts.WordMode != WordsSpace && opts.WordMode != "" || opts.Language != nil {
+		return false
+	}
+	if opts.CharsAreBytes {
+		return true
+	}
+	bom := make([]byte, 2)
+	n, _ := reader.ReadAt(bom, 0)
+	return utf16ByteOrder(bom[:n]) == nil
+}
+
+// chunkOffsets returns the chunk edges, from 0 to size. Each inner edge is
+// moved past up to three continuation bytes, which puts it at the start of a
+// rune or after bytes that would be decoded as invalid runes on their own.
+// Either way the chunks decode exactly like the whole input.
+func chunkOffsets(reader io.ReaderAt, size int64, chunks int) ([]int64, error) {
+	offsets := []int64{0}
+	chunkSize := size / int64(chunks)
+	for i := 1; i < chunks; i++ {
+		offset := int64(i) * chunkSize
+		if offset <= offsets[len(offsets)-1] {
+			continue
+		}
+
+		edge := make([]byte, utf8.UTFMax-1)
+		n, err := reader.ReadAt(edge, offset)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		for _, b := range edge[:n] {
+			if utf8.RuneStart(b) {
+				break
+			}
+			offset++
+		}
+		if offset < size {
+			offsets = append(offsets, offset)
+		}
+	}
+	return append(offsets, size), nil
+}
+
+func countChunk(reader io.ReaderAt, start, end int64, progress func(n int64)) chunkResult {
+	var result chunkResult
+
+	first := make([]byte, utf8.UTFMax)
+	n, err := reader.ReadAt(first[:min(int64(len(first)), end-start)], start)
+	if err != nil && err != io.EOF {
+		return chunkResult{err: err}
+	}
+	if n > 0 {
+		r, _ := utf8.DecodeRune(first[:n])
+		result.startsInWord = !unicode.IsSpace(r)
+	}
+
+	var chunk io.Reader = io.NewSectionReader(reader, start, end-start)
+	if progress != nil {
+		chunk = &progressReader{reader: chunk, progress: progress}
+	}
+	var c Counter
+	if _, err := c.ReadFrom(chunk); err != nil {
+		return chunkResult{err: err}
+	}
+	c.Flush()
+
+	result.Counts = c.counts
+	result.endsInWord = c.inWord
+	result.lastByte = c.lastByte
+	result.hasBreak = c.sawBreak
+	result.lineWidth = c.lineWidth
+	result.prefix = linePrefix{width: c.lineWidth, tabbed: c.prefixTabbed, tab: c.prefixTab}
+	if c.sawBreak {
+		result.prefix.width = c.prefixWidth
+	}
+	return result
+}
+
+// progressReader calls progress with the size of every read.
+type progressReader struct {
+	reader   io.Reader
+	progress func(n int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.progress(int64(n))
+	return n, err
+}
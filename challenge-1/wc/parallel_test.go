@@ -0,0 +1,72 @@
+package wc
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_CountParallel(t *testing.T) {
+	testcases := map[string]string{
+		"Should merge words across chunks":     strings.Repeat("lorem ipsum dolor", 50),
+		"Should not split multibyte runes":     strings.Repeat("เสือกระโดด ๏ ท\n", 40),
+		"Should handle unicode spaces":         strings.Repeat("a　b c d", 30),
+		"Should handle invalid utf-8":          strings.Repeat("x\xe2\x82 \x80\x80\x80\x80y\xff", 30),
+		"Should count an unterminated line":    "one\ntwo\nthree",
+		"Should count a single chunk of input": "a",
+		"Should measure lines across chunks":   strings.Repeat("ab\tcd\u3042\te ", 20) + "\n" + strings.Repeat("x\t", 30),
+	}
+
+	for k, input := range testcases {
+		expected, err := Count(strings.NewReader(input), Options{})
+		if err != nil {
+			t.Error(k, err)
+		}
+		for chunks := 1; chunks <= 16; chunks++ {
+			actual, err := CountParallel(strings.NewReader(input), int64(len(input)), chunks, Options{})
+			if err != nil {
+				t.Error(k, err)
+			}
+			if actual != expected {
+				t.Error(k, "Chunks", chunks, "Expected:", expected, "Actual", actual)
+			}
+		}
+	}
+}
+
+func Test_CountParallelFile(t *testing.T) {
+	file, err := os.Open("../file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	meta, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := CountParallel(file, meta.Size(), 7, Options{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := Counts{Lines: 23, Words: 549, Chars: 3566, Bytes: 3568, MaxLineLength: 818}
+	if actual != expected {
+		t.Error("Expected:", expected, "Actual", actual)
+	}
+}
+
+func Test_CountParallelProgress(t *testing.T) {
+	input := strings.Repeat("เสือกระโดด ๏ ท\n", 40)
+	for chunks := 1; chunks <= 16; chunks++ {
+		var done atomic.Int64
+		opts := Options{Progress: func(n int64) { done.Add(n) }}
+		if _, err := CountParallel(strings.NewReader(input), int64(len(input)), chunks, opts); err != nil {
+			t.Error(err)
+		}
+		if actual := done.Load(); actual != int64(len(input)) {
+			t.Error("Chunks", chunks, "Expected:", len(input), "Actual", actual)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package wc counts the lines, words, characters and bytes of a text the way
+// the wc command does.
+//
+// A Counter is an io.Writer, so it can be fed from io.Copy, an
+// io.MultiWriter or a logger while the data goes elsewhere:
+//
+//	counter, _ := wc.NewCounter(wc.Options{})
+//	io.Copy(counter, file)
+//	counter.Flush()
+//	fmt.Println(counter.Counts().Lines)
+package wc
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+const bufferSize = 64 * 1024
+
+// Counts holds the metrics gathered for one input.
+type Counts struct {
+	Lines int
+	Words int
+	Chars int
+	Bytes int
+
+	// MaxLineLength is the display width of the longest line, with tabs
+	// expanded to 8 columns and wide runes taking 2.
+	MaxLineLength int
+
+	// Blank, Comment and Code split Lines by what they hold. They are only
+	// counted when Options.Language is set.
+	Blank   int
+	Comment int
+	Code    int
+
+	// DistinctLines and DistinctWords are the number of unique lines and
+	// words, for callers that track them; Add leaves them alone, since the
+	// distinct items of two inputs do not add up.
+	DistinctLines int
+	DistinctWords int
+}
+
+// Add adds other to c, as for a total. The total max line length is the
+// largest of the two, and distinct counts are not added.
+func (c *Counts) Add(other Counts) {
+	c.Lines += other.Lines
+	c.Words += other.Words
+	c.Chars += other.Chars
+	c.Bytes += other.Bytes
+	c.MaxLineLength = max(c.MaxLineLength, other.MaxLineLength)
+	c.Blank += other.Blank
+	c.Comment += other.Comment
+	c.Code += other.Code
+}
+
+// Options selects how a Counter splits words and counts characters. The zero
+// value splits words on Unicode white space and decodes UTF-8.
+type Options struct {
+	// WordMode is one of WordsSpace, WordsPOSIX or WordsUAX29.
+	WordMode string
+	// CharsAreBytes counts every byte as a character, as wc -m does in
+	// the C locale.
+	CharsAreBytes bool
+	// Language, when set, sorts the lines into blank, comment and code
+	// lines following its syntax.
+	Language *Language
+	// Progress, when set, is called by CountParallel with the size of every
+	// read of the chunks, which add up to the size of the input. The small
+	// reads that find the chunk edges are left out.
+	Progress func(n int64)
+}
+
+// Count counts reader until EOF. Unless CharsAreBytes is set, input that
+// starts with a UTF-16 byte order mark is decoded before counting; Bytes is
+// still the size of the input as is.
+func Count(reader io.Reader, opts Options) (Counts, error) {
+	c, err := NewCounter(opts)
+	if err != nil {
+		return Counts{}, err
+	}
+	if opts.CharsAreBytes {
+		if _, err := c.ReadFrom(reader); err != nil {
+			return Counts{}, err
+		}
+		c.Flush()
+		return c.Counts(), nil
+	}
+
+	buffered := bufio.NewReaderSize(reader, bufferSize)
+	bom, _ := buffered.Peek(2)
+	order := utf16ByteOrder(bom)
+	if order == nil {
+		if _, err := c.ReadFrom(buffered); err != nil {
+			return Counts{}, err
+		}
+		c.Flush()
+		return c.Counts(), nil
+	}
+
+	// The byte order mark only tells the encoding, it is not part of the
+	// text, but its bytes still count.
+	buffered.Discard(len(bom))
+	decoder := &utf16Writer{w: c, order: order, bytes: len(bom)}
+	if _, err := io.Copy(decoder, buffered); err != nil {
+		return Counts{}, err
+	}
+	decoder.flush()
+	c.Flush()
+	result := c.Counts()
+	result.Bytes = decoder.bytes
+	return result, nil
+}
+
+// CountBytes counts data held in memory, such as a memory-mapped file,
+// without copying it.
+func CountBytes(data []byte, opts Options) (Counts, error) {
+	if !opts.CharsAreBytes && utf16ByteOrder(data[:min(len(data), 2)]) != nil {
+		return Count(bytes.NewReader(data), opts)
+	}
+	c, err := NewCounter(opts)
+	if err != nil {
+		return Counts{}, err
+	}
+	c.Write(data)
+	c.Flush()
+	return c.Counts(), nil
+}
@@ -0,0 +1,25 @@
+package wc
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_CountBytes(t *testing.T) {
+	inputs := map[string]string{
+		"Should count like Count":         "one two\nthree ท",
+		"Should decode UTF-16 like Count": "\xff\xfeo\x00n\x00e\x00 \x00\x17\x0e\n\x00",
+		"Should count empty data":         "",
+	}
+
+	for k, input := range inputs {
+		expected, _ := Count(strings.NewReader(input), Options{})
+		actual, err := CountBytes([]byte(input), Options{})
+		if err != nil {
+			t.Error(k, err)
+		}
+		if actual != expected {
+			t.Error(k, "Expected:", expected, "Actual", actual)
+		}
+	}
+}
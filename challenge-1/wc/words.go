@@ -0,0 +1,211 @@
+package wc
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Word modes for Options.WordMode.
+const (
+	WordsSpace = "space" // split on Unicode white space, as bufio.ScanWords does
+	WordsPOSIX = "posix" // split on the POSIX white space characters only
+	WordsUAX29 = "uax29" // Unicode word boundaries
+)
+
+// wordSplitter finds where words start, one rune at a time. Invalid bytes
+// are passed as utf8.RuneError.
+type wordSplitter interface {
+	startsWord(r rune) bool
+}
+
+// newWordSplitter returns the splitter for a word mode. The default mode
+// has no splitter, the counter handles it inline since it is the hot path.
+func newWordSplitter(mode string) (wordSplitter, error) {
+	switch mode {
+	case WordsSpace, "":
+		return nil, nil
+	case WordsPOSIX:
+		return &posixSplitter{}, nil
+	case WordsUAX29:
+		return &uax29Splitter{}, nil
+	}
+	return nil, fmt.Errorf("invalid word mode %q, expecting %s, %s or %s", mode, WordsSpace, WordsPOSIX, WordsUAX29)
+}
+
+// posixSplitter splits on space, tab, newline, vertical tab, form feed and
+// carriage return. Every other rune, including Unicode spaces, is part of a
+// word.
+type posixSplitter struct {
+	inWord bool
+}
+
+func (p *posixSplitter) startsWord(r rune) bool {
+	if r < 0x80 && isSpaceByte(byte(r)) {
+		p.inWord = false
+		return false
+	}
+	starts := !p.inWord
+	p.inWord = true
+	return starts
+}
+
+// wordClass is the Word_Break property of UAX #29, approximated from the
+// general categories and scripts in the unicode package.
+type wordClass int
+
+const (
+	classOther wordClass = iota
+	classLetter
+	classNumeric
+	classKatakana
+	classExtendNumLet
+	classMidLetter
+	classMidNum
+	classMidNumLet
+	classExtend
+	classIdeographic // Han and Hiragana, where every rune is a word
+	classComplex     // scripts written without spaces, such as Thai
+)
+
+// uax29Splitter counts the segments between UAX #29 word boundaries that
+// hold a letter, digit or ideograph, which is how word processors count
+// words. Segmenting scripts written without spaces needs a dictionary, so a
+// run of them counts as a single word.
+type uax29Splitter struct {
+	prev    wordClass // class of the last rune, ignoring Extend
+	mid     wordClass // a MidLetter, MidNum or MidNumLet waiting on the next rune
+	counted bool      // whether the current segment was counted
+}
+
+func (u *uax29Splitter) startsWord(r rune) bool {
+	class := classifyWord(r)
+	if class == classExtend {
+		// WB4: marks belong to the rune before them.
+		return false
+	}
+
+	if u.mid != classOther {
+		mid := u.mid
+		u.mid = classOther
+		if joinsAcross(u.prev, mid, class) {
+			// WB6, WB7, WB11 and WB12, such as can't or 3.14.
+			u.prev = class
+			return false
+		}
+		u.prev = mid
+	}
+
+	if takesMid(u.prev, class) {
+		u.mid = class
+		return false
+	}
+
+	if !joins(u.prev, class) {
+		u.counted = false
+	}
+	u.prev = class
+	if isWordLike(class) && !u.counted {
+		u.counted = true
+		return true
+	}
+	return false
+}
+
+// joins reports whether there is no boundary between two adjacent classes,
+// per rules WB5 to WB13b.
+func joins(prev, next wordClass) bool {
+	switch {
+	case isAlphanumeric(prev) && isAlphanumeric(next):
+		return true
+	case prev == classKatakana && next == classKatakana:
+		return true
+	case next == classExtendNumLet:
+		return isAlphanumeric(prev) || prev == classKatakana || prev == classExtendNumLet
+	case prev == classExtendNumLet:
+		return isAlphanumeric(next) || next == classKatakana
+	case prev == classComplex && next == classComplex:
+		return true
+	}
+	return false
+}
+
+// takesMid reports whether mid may join prev to a following rune.
+func takesMid(prev, mid wordClass) bool {
+	switch mid {
+	case classMidLetter:
+		return prev == classLetter
+	case classMidNum:
+		return prev == classNumeric
+	case classMidNumLet:
+		return prev == classLetter || prev == classNumeric
+	}
+	return false
+}
+
+func joinsAcross(prev, mid, next wordClass) bool {
+	if prev == classLetter && next == classLetter {
+		return mid == classMidLetter || mid == classMidNumLet
+	}
+	if prev == classNumeric && next == classNumeric {
+		return mid == classMidNum || mid == classMidNumLet
+	}
+	return false
+}
+
+func isAlphanumeric(class wordClass) bool {
+	return class == classLetter || class == classNumeric
+}
+
+func isWordLike(class wordClass) bool {
+	switch class {
+	case classLetter, classNumeric, classKatakana, classIdeographic, classComplex:
+		return true
+	}
+	return false
+}
+
+func classifyWord(r rune) wordClass {
+	switch r {
+	case ':', '\u00b7', '\u0387', '\u055f', '\u05f4', '\u2027', '\ufe13',
+		'\ufe55', '\uff1a':
+		return classMidLetter
+	case '.', '\'', '\u2018', '\u2019', '\u2024', '\ufe52', '\uff07',
+		'\uff0e':
+		return classMidNumLet
+	case ',', ';', '\u037e', '\u0589', '\u060c', '\u060d', '\u066c',
+		'\u07f8', '\u2044', '\ufe10', '\ufe14', '\ufe50', '\ufe54', '\uff0c',
+		'\uff1b':
+		return classMidNum
+	case '\u3031', '\u3032', '\u3033', '\u3034', '\u3035', '\u309b',
+		'\u309c', '\u30a0', '\u30fc', '\uff70':
+		return classKatakana
+	}
+
+	switch {
+	case r < 0x80:
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+			return classLetter
+		case '0' <= r && r <= '9':
+			return classNumeric
+		case r == '_':
+			return classExtendNumLet
+		}
+		return classOther
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Cf):
+		return classExtend
+	case unicode.Is(unicode.Katakana, r):
+		return classKatakana
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		return classIdeographic
+	case unicode.In(r, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar):
+		return classComplex
+	case unicode.IsLetter(r):
+		return classLetter
+	case unicode.Is(unicode.Nd, r):
+		return classNumeric
+	case unicode.Is(unicode.Pc, r):
+		return classExtendNumLet
+	}
+	return classOther
+}
@@ -0,0 +1,45 @@
+package wc
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_wordModes(t *testing.T) {
+	testcases := map[string]struct {
+		mode     string
+		input    string
+		expected int
+	}{
+		"Should split on unicode spaces":              {mode: WordsSpace, input: "one two three", expected: 3},
+		"Should split on posix spaces only":           {mode: WordsPOSIX, input: "one two three", expected: 2},
+		"Should count punctuation as posix words":     {mode: WordsPOSIX, input: "one - two", expected: 3},
+		"Should not count punctuation as uax29 words": {mode: WordsUAX29, input: "one - two, (three)!", expected: 3},
+		"Should keep apostrophes inside words":        {mode: WordsUAX29, input: "can't won’t", expected: 2},
+		"Should keep decimal numbers together":        {mode: WordsUAX29, input: "3.14 1,000", expected: 2},
+		"Should split trailing punctuation":           {mode: WordsUAX29, input: "end. start", expected: 2},
+		"Should split words joined by hyphens":        {mode: WordsUAX29, input: "well-known", expected: 2},
+		"Should join underscores":                     {mode: WordsUAX29, input: "snake_case _private", expected: 2},
+		"Should count every ideograph":                {mode: WordsUAX29, input: "日本語", expected: 3},
+		"Should join katakana":                        {mode: WordsUAX29, input: "カタカナ テスト", expected: 2},
+		"Should keep combining marks in the word":     {mode: WordsUAX29, input: "café au lait", expected: 3},
+		"Should count a run of thai as one word":      {mode: WordsUAX29, input: "เสือกระโดด ท", expected: 2},
+		"Should split on punctuation without spaces":  {mode: WordsUAX29, input: "a/b|c", expected: 3},
+	}
+
+	for k, v := range testcases {
+		actual, err := Count(strings.NewReader(v.input), Options{WordMode: v.mode})
+		if err != nil {
+			t.Error(k, err)
+		}
+		if actual.Words != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual", actual.Words)
+		}
+	}
+}
+
+func Test_newWordSplitter(t *testing.T) {
+	if _, err := newWordSplitter("letters"); err == nil {
+		t.Error("Expecting error for an unknown word mode")
+	}
+}
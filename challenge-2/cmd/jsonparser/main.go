@@ -0,0 +1,62 @@
+// Command jsonparser checks that files are valid JSON. It reports each file
+// on standard output and exits with 0 when all of them are valid, or 1 when
+// one is not. With no files, it checks standard input.
+//
+//	jsonparser [file ...]
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	parser "github.com/jawahars16/john-crickett-coding-challenges/challenge-2"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run checks the files and returns the exit code.
+func run(filePaths []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(filePaths) == 0 {
+		return check("-", stdin, stdout, stderr)
+	}
+	code := 0
+	for _, filePath := range filePaths {
+		file, err := os.Open(filePath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = 1
+			continue
+		}
+		code = max(code, check(filePath, file, stdout, stderr))
+		file.Close()
+	}
+	return code
+}
+
+// check reports whether the document read from reader is valid, showing
+// where it is not.
+func check(name string, reader io.Reader, stdout, stderr io.Writer) int {
+	document, err := io.ReadAll(reader)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+
+	_, err = parser.ParseValue(bytes.NewReader(document))
+	var syntaxErr *parser.SyntaxError
+	switch {
+	case err == nil:
+		fmt.Fprintf(stdout, "%s: valid JSON\n", name)
+		return 0
+	case errors.As(err, &syntaxErr):
+		fmt.Fprintf(stdout, "%s: %v\n%s", name, err, syntaxErr.Caret(document))
+	default:
+		fmt.Fprintf(stdout, "%s: %v\n", name, err)
+	}
+	return 1
+}
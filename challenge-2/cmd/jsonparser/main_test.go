@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(valid, []byte(`{"a": [1, 2]}`), 0o644)
+	os.WriteFile(invalid, []byte("{\n  \"a\": [1 2]\n}\n"), 0o644)
+
+	testcases := map[string]struct {
+		args     []string
+		stdin    string
+		code     int
+		expected string
+	}{
+		"valid file": {
+			args:     []string{valid},
+			expected: valid + ": valid JSON\n",
+		},
+		"invalid file": {
+			args:     []string{invalid},
+			code:     1,
+			expected: invalid + ": line 2, column 11: Invalid array. Expecting ',' or ']', found 2.\n  \"a\": [1 2]\n          ^\n",
+		},
+		"one invalid file of two": {
+			args:     []string{invalid, valid},
+			code:     1,
+			expected: valid + ": valid JSON\n",
+		},
+		"stdin": {
+			stdin:    "true",
+			expected: "-: valid JSON\n",
+		},
+		"missing file": {
+			args: []string{filepath.Join(dir, "missing.json")},
+			code: 1,
+		},
+	}
+
+	for k, testCase := range testcases {
+		var stdout, stderr bytes.Buffer
+		code := run(testCase.args, strings.NewReader(testCase.stdin), &stdout, &stderr)
+		if code != testCase.code {
+			t.Error(k, "Expected:", testCase.code, "Actual:", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), testCase.expected) {
+			t.Error(k, "Expected:", testCase.expected, "Actual:", stdout.String())
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// excerptWidth is how many characters of a long line Caret shows around the
+// error.
+const excerptWidth = 72
+
+// SyntaxError is an invalid document, with where it stops being valid. The
+// grammar fills in Expected, and Found unless the document ended; the lexer
+// only gives a message. Column counts characters, not screen columns.
+type SyntaxError struct {
+	Position
+	Msg      string
+	Expected string
+	Found    string
+}
+
+func (e *SyntaxError) Error() string {
+	msg := e.Msg
+	if e.Expected != "" {
+		msg += " Expecting " + e.Expected
+		if e.Found != "" {
+			msg += ", found " + e.Found
+		}
+		msg += "."
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+}
+
+// Caret renders the line of the document with the error, with a caret
+// under the error on the line after it, wide characters taking two columns.
+// A long line is cut down around the error.
+func (e *SyntaxError) Caret(document []byte) string {
+	offset := min(max(e.Offset, 0), len(document))
+	lineStart := bytes.LastIndexByte(document[:offset], '\n') + 1
+	lineEnd := len(document)
+	if i := bytes.IndexByte(document[offset:], '\n'); i >= 0 {
+		lineEnd = offset + i
+	}
+	before := []rune(string(document[lineStart:offset]))
+	after := []rune(strings.TrimRight(string(document[offset:lineEnd]), "\r"))
+
+	prefix, suffix := "", ""
+	if len(before) > excerptWidth/2 {
+		before = before[len(before)-excerptWidth/2:]
+		prefix = "..."
+	}
+	if len(after) > excerptWidth/2 {
+		after = after[:excerptWidth/2]
+		suffix = "..."
+	}
+
+	var caret strings.Builder
+	caret.WriteString(strings.Repeat(" ", utf8.RuneCountInString(prefix)))
+	for _, r := range before {
+		// Keep tabs, so that the caret lines up however wide they show,
+		// and take as many columns as the characters above.
+		if r == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteString(strings.Repeat(" ", runeWidth(r)))
+		}
+	}
+	caret.WriteRune('^')
+	return prefix + string(before) + string(after) + suffix + "\n" + caret.String() + "\n"
+}
+
+// describe names a token for a SyntaxError.
+func describe(token Token) string {
+	switch token.Type {
+	case StringLiteral:
+		return fmt.Sprintf("string %q", token.Value)
+	case NumericLiteral, BooleanLiteral, NullLiteral:
+		return token.Value
+	}
+	return "'" + token.Value + "'"
+}
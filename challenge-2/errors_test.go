@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_tokenPositions(t *testing.T) {
+	tokens, end, err := tokenize(strings.NewReader("{\"é\":\r\n  [1, \"x\"]}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Position{
+		{Offset: 0, Line: 1, Column: 1},
+		{Offset: 1, Line: 1, Column: 2},
+		{Offset: 5, Line: 1, Column: 5},
+		{Offset: 10, Line: 2, Column: 3},
+		{Offset: 11, Line: 2, Column: 4},
+		{Offset: 12, Line: 2, Column: 5},
+		{Offset: 14, Line: 2, Column: 7},
+		{Offset: 17, Line: 2, Column: 10},
+		{Offset: 18, Line: 2, Column: 11},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatal("Expected:", len(expected), "Actual:", len(tokens))
+	}
+	for k, token := range tokens {
+		if token.Position != expected[k] {
+			t.Error(k, token.Value, "Expected:", expected[k], "Actual:", token.Position)
+		}
+	}
+	if expectedEnd := (Position{Offset: 20, Line: 3, Column: 1}); end != expectedEnd {
+		t.Error("Expected:", expectedEnd, "Actual:", end)
+	}
+}
+
+func Test_syntaxErrors(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected SyntaxError
+	}{
+		"missing comma": {
+			input:    "[1,\n 2 3]",
+			expected: SyntaxError{Position: Position{Offset: 7, Line: 2, Column: 4}, Msg: "Invalid array.", Expected: "',' or ']'", Found: "3"},
+		},
+		"missing colon": {
+			input:    `{"a" 1}`,
+			expected: SyntaxError{Position: Position{Offset: 5, Line: 1, Column: 6}, Msg: "Invalid object expression.", Expected: "':'", Found: "1"},
+		},
+		"key not a string": {
+			input:    `{"a":1,true:2}`,
+			expected: SyntaxError{Position: Position{Offset: 7, Line: 1, Column: 8}, Msg: "Invalid object expression.", Expected: "a string key", Found: "true"},
+		},
+		"trailing value": {
+			input:    `{} "x"`,
+			expected: SyntaxError{Position: Position{Offset: 3, Line: 1, Column: 4}, Msg: "Unexpected token after the value.", Expected: "end of file", Found: `string "x"`},
+		},
+		"end of file": {
+			input:    "[1,\n",
+			expected: SyntaxError{Position: Position{Offset: 4, Line: 2, Column: 1}, Msg: "Unexpected end of file.", Expected: "a value"},
+		},
+		"invalid escape": {
+			input:    `["ab\q"]`,
+			expected: SyntaxError{Position: Position{Offset: 4, Line: 1, Column: 5}, Msg: `Invalid escape sequence '\q' in string literal.`},
+		},
+		"leading zero": {
+			input:    "[-012]",
+			expected: SyntaxError{Position: Position{Offset: 3, Line: 1, Column: 4}, Msg: "Numeric literal cannot begin with 0."},
+		},
+		"unknown character": {
+			input:    "[é, @]",
+			expected: SyntaxError{Position: Position{Offset: 1, Line: 1, Column: 2}, Msg: "Unexpected character 'é'."},
+		},
+	}
+
+	for k, testCase := range testcases {
+		_, err := ParseValue(strings.NewReader(testCase.input))
+		var actual *SyntaxError
+		if !errors.As(err, &actual) {
+			t.Error(k, "Expecting a SyntaxError", err)
+			continue
+		}
+		if *actual != testCase.expected {
+			t.Error(k, "Expected:", testCase.expected, "Actual:", *actual)
+		}
+	}
+}
+
+func Test_SyntaxErrorMessage(t *testing.T) {
+	testcases := map[string]struct {
+		err      SyntaxError
+		expected string
+	}{
+		"found": {
+			err:      SyntaxError{Position: Position{Offset: 7, Line: 2, Column: 4}, Msg: "Invalid array.", Expected: "',' or ']'", Found: "3"},
+			expected: "line 2, column 4: Invalid array. Expecting ',' or ']', found 3.",
+		},
+		"end of file": {
+			err:      SyntaxError{Position: Position{Offset: 3, Line: 1, Column: 4}, Msg: "Unexpected end of file.", Expected: "a value"},
+			expected: "line 1, column 4: Unexpected end of file. Expecting a value.",
+		},
+		"lexer": {
+			err:      SyntaxError{Position: Position{Offset: 1, Line: 1, Column: 2}, Msg: "Unexpected character '@'."},
+			expected: "line 1, column 2: Unexpected character '@'.",
+		},
+	}
+
+	for k, testCase := range testcases {
+		if actual := testCase.err.Error(); actual != testCase.expected {
+			t.Error(k, "Expected:", testCase.expected, "Actual:", actual)
+		}
+	}
+}
+
+func Test_Caret(t *testing.T) {
+	long := strings.Repeat("1,", 50)
+	testcases := map[string]struct {
+		document string
+		expected string
+	}{
+		"first line": {
+			document: "[1 2]\n",
+			expected: "[1 2]\n   ^\n",
+		},
+		"tabs and crlf": {
+			document: "{\r\n\t\"a\": x\r\n}",
+			expected: "\t\"a\": x\n\t     ^\n",
+		},
+		"multibyte": {
+			document: `["é" x]`,
+			expected: "[\"é\" x]\n     ^\n",
+		},
+		"wide characters": {
+			document: `["é日本" x]`,
+			expected: "[\"é日本\" x]\n         ^\n",
+		},
+		"end of file": {
+			document: "[1,\n",
+			expected: "\n^\n",
+		},
+		"long line": {
+			document: "[" + long + "x" + long + "1]",
+			expected: "..." + long[len(long)-36:] + "x" + long[:35] + "...\n" + strings.Repeat(" ", 39) + "^\n",
+		},
+	}
+
+	for k, testCase := range testcases {
+		_, err := ParseValue(strings.NewReader(testCase.document))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Error(k, "Expecting a SyntaxError", err)
+			continue
+		}
+		actual := syntaxErr.Caret([]byte(testCase.document))
+		if actual != testCase.expected {
+			t.Errorf("%s Expected:\n%q\nActual:\n%q", k, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package parser
+
+import "fmt"
+
+// endOfFile is what a SyntaxError expects after the value.
+const endOfFile = "end of file"
+
+// parser is a recursive-descent parser over the tokens of a document, with
+// one method per rule of the grammar in RFC 8259:
+//
+//	JSON-text = value
+//	value     = object / array / string / number / false / null / true
+//	object    = "{" [ member *( "," member ) ] "}"
+//	member    = string ":" value
+//	array     = "[" [ value *( "," value ) ] "]"
+type parser struct {
+	tokens []Token
+	next   int
+	depth  int
+	end    Position
+}
+
+// parse checks that the tokens make exactly one value, and returns it. end
+// is the position of the end of the document.
+func parse(tokens []Token, end Position) (Value, error) {
+	p := &parser{tokens: tokens, end: end}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if p.next < len(p.tokens) {
+		return nil, unexpected(p.tokens[p.next], "Unexpected token after the value.", endOfFile)
+	}
+	return value, nil
+}
+
+// advance consumes the next token, where the grammar expects expected.
+func (p *parser) advance(expected string) (Token, error) {
+	if p.next == len(p.tokens) {
+		return Token{}, &SyntaxError{Position: p.end, Msg: "Unexpected end of file.", Expected: expected}
+	}
+	token := p.tokens[p.next]
+	p.next++
+	return token, nil
+}
+
+// skip consumes the next token when it has the type t.
+func (p *parser) skip(t TokenType) bool {
+	if p.next < len(p.tokens) && p.tokens[p.next].Type == t {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func unexpected(token Token, msg, expected string) error {
+	return &SyntaxError{Position: token.Position, Msg: msg, Expected: expected, Found: describe(token)}
+}
+
+func (p *parser) value() (Value, error) {
+	token, err := p.advance("a value")
+	if err != nil {
+		return nil, err
+	}
+	switch token.Type {
+	case NullLiteral:
+		return Null{}, nil
+	case BooleanLiteral:
+		return Bool(token.Value == trueLiteral), nil
+	case NumericLiteral:
+		return Number(token.Value), nil
+	case StringLiteral:
+		return String(token.Value), nil
+	case ArrayOpener:
+		return p.nested(token, p.array)
+	case ObjectOpener:
+		return p.nested(token, p.object)
+	}
+	return nil, unexpected(token, "Unexpected token.", "a value")
+}
+
+// nested parses an array or an object, one level deeper.
+func (p *parser) nested(opener Token, rule func() (Value, error)) (Value, error) {
+	if p.depth == maxDepth {
+		return nil, &SyntaxError{Position: opener.Position, Msg: fmt.Sprintf("Nesting deeper than %d levels.", maxDepth)}
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	return rule()
+}
+
+// array parses the rest of an array, after its opener.
+func (p *parser) array() (Value, error) {
+	array := Array{}
+	if p.skip(ArrayCloser) {
+		return array, nil
+	}
+	for {
+		item, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+
+		const expected = "',' or ']'"
+		token, err := p.advance(expected)
+		if err != nil {
+			return nil, err
+		}
+		switch token.Type {
+		case ArrayCloser:
+			return array, nil
+		case ItemSepartor:
+		default:
+			return nil, unexpected(token, "Invalid array.", expected)
+		}
+	}
+}
+
+// object parses the rest of an object, after its opener.
+func (p *parser) object() (Value, error) {
+	object := Object{}
+	if p.skip(ObjectCloser) {
+		return object, nil
+	}
+	for {
+		const expectedKey = "a string key"
+		key, err := p.advance(expectedKey)
+		if err != nil {
+			return nil, err
+		}
+		if key.Type != StringLiteral {
+			return nil, unexpected(key, "Invalid object expression.", expectedKey)
+		}
+		separator, err := p.advance("':'")
+		if err != nil {
+			return nil, err
+		}
+		if separator.Type != KeyValueSeparator {
+			return nil, unexpected(separator, "Invalid object expression.", "':'")
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		object.Members = append(object.Members, Member{Key: key.Value, Value: value})
+
+		const expected = "',' or '}'"
+		token, err := p.advance(expected)
+		if err != nil {
+			return nil, err
+		}
+		switch token.Type {
+		case ObjectCloser:
+			return object, nil
+		case ItemSepartor:
+		default:
+			return nil, unexpected(token, "Invalid object expression.", expected)
+		}
+	}
+}
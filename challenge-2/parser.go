@@ -1,12 +1,13 @@
 package parser
 
 import (
-	"container/list"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -25,318 +26,347 @@ const (
 	NullLiteral       TokenType = 10
 )
 
-const (
-	ObjectContext int = 0
-	ArrayContext  int = 1
-)
-
 const (
 	trueLiteral  string = "true"
 	falseLiteral string = "false"
 	nullLiteral  string = "null"
 )
 
+// Position locates a token or an error in a document. Offset counts bytes
+// from 0, Line and Column count from 1, with Column counting characters.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
 type Token struct {
 	Type  TokenType
 	Value string
+	Position
 }
 
+// maxDepth limits how deeply arrays and objects nest, so that a document
+// cannot exhaust the stack of the parser.
+const maxDepth = 10000
+
 func Parse(reader io.Reader) error {
-	tokens, err := tokenize(reader)
-	if err != nil {
-		return err
-	}
-	err = checkGrammar(tokens)
+	_, err := ParseValue(reader)
+	return err
+}
+
+// ParseValue parses the document and returns its tree. An invalid document
+// gives a *SyntaxError.
+func ParseValue(reader io.Reader) (Value, error) {
+	tokens, end, err := tokenize(reader)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return parse(tokens, end)
 }
 
-func tokenize(reader io.Reader) ([]Token, error) {
-	buffer := make([]byte, 1024)
+// tokenize returns the tokens of the document and the position of its end.
+func tokenize(reader io.Reader) ([]Token, Position, error) {
+	// The whole document is read first, so that no token is split between
+	// two reads.
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, Position{}, err
+	}
 	var tokens []Token
+	at := cursor{data: data, position: Position{Line: 1, Column: 1}}
+	start := 0
 	for {
-		n, err := reader.Read(buffer)
-		start := 0
-		for start < n {
-			advance, token, err := scan(buffer[start:], err == io.EOF)
-			if err != nil {
-				start = 0
-				return tokens, err
-			}
-			if token != nil {
-				tokens = append(tokens, *token)
-			}
-			start = start + advance
-		}
-
-		if err == io.EOF {
+		start = skipWhitespace(data, start)
+		if start == len(data) {
 			break
 		}
+		length, token, err := scan(data[start:])
+		if err != nil {
+			return tokens, Position{}, &SyntaxError{Position: at.moveTo(start + length), Msg: err.Error()}
+		}
+		token.Position = at.moveTo(start)
+		tokens = append(tokens, *token)
+		start = start + length
 	}
-	return tokens, nil
+	return tokens, at.moveTo(len(data)), nil
 }
 
-func scan(data []byte, atEOF bool) (advance int, token *Token, err error) {
-	var current, width int
-	// Skip all the spaces
-	for current = 0; current < len(data); current += width {
-		var r rune
-		r, width = utf8.DecodeRune(data[current:])
-		if !unicode.IsSpace(r) {
-			break
-		}
+// cursor follows the line and column of an offset moving forward through a
+// document, so that finding them does not take a pass from the start.
+type cursor struct {
+	data     []byte
+	position Position
+}
+
+func (c *cursor) moveTo(offset int) Position {
+	passed := c.data[c.position.Offset:offset]
+	if lastNewline := bytes.LastIndexByte(passed, '\n'); lastNewline >= 0 {
+		c.position.Line += bytes.Count(passed, []byte{'\n'})
+		c.position.Column = utf8.RuneCount(passed[lastNewline+1:]) + 1
+	} else {
+		c.position.Column += utf8.RuneCount(passed)
 	}
+	c.position.Offset = offset
+	return c.position
+}
 
-	// Check if the current pointer is at expected token
-	for current < len(data) {
-		var r rune
-		r, width = utf8.DecodeRune(data[current:])
-		current += width
+func skipWhitespace(data []byte, current int) int {
+	for current < len(data) && isWhitespace(rune(data[current])) {
+		current++
+	}
+	return current
+}
 
-		if isObjectOpener(r) {
-			return current, &Token{Type: ObjectOpener, Value: string(r)}, nil
-		}
+// scan reads the token at the start of data and returns its length. On
+// error, it returns where the error is instead.
+func scan(data []byte) (length int, token *Token, err error) {
+	r, width := utf8.DecodeRune(data)
+	current := width
 
-		if isObjectCloser(r) {
-			return current, &Token{Type: ObjectCloser, Value: string(r)}, nil
-		}
+	if isObjectOpener(r) {
+		return current, &Token{Type: ObjectOpener, Value: string(r)}, nil
+	}
 
-		if isArrayOpener(r) {
-			return current, &Token{Type: ArrayOpener, Value: string(r)}, nil
-		}
+	if isObjectCloser(r) {
+		return current, &Token{Type: ObjectCloser, Value: string(r)}, nil
+	}
 
-		if isArrayCloser(r) {
-			return current, &Token{Type: ArrayCloser, Value: string(r)}, nil
-		}
+	if isArrayOpener(r) {
+		return current, &Token{Type: ArrayOpener, Value: string(r)}, nil
+	}
 
-		if isQuote(r) {
-			// we found a quote, now grab the literal
-			literal, width, err := grabStringLiteral(data[current:])
-			if err != nil {
-				return current, nil, err
-			}
-			return current + width, &Token{Type: StringLiteral, Value: literal}, nil
-		}
+	if isArrayCloser(r) {
+		return current, &Token{Type: ArrayCloser, Value: string(r)}, nil
+	}
 
-		if unicode.IsDigit(r) {
-			literal, width, err := grabNumericLiteral(data[current-width:])
-			if err != nil {
-				return current, nil, err
-			}
-			return current + width, &Token{Type: NumericLiteral, Value: literal}, nil
+	if isQuote(r) {
+		// we found a quote, now grab the literal
+		literal, width, err := grabStringLiteral(data[current:])
+		if err != nil {
+			return current + width, nil, err
 		}
+		return current + width, &Token{Type: StringLiteral, Value: literal}, nil
+	}
 
-		if ok, bLiteral := getBooleanLiteral(data[current-1:]); ok {
-			return current - 1 + len(bLiteral), &Token{Type: BooleanLiteral, Value: bLiteral}, nil
+	if isDigit(r) || r == '-' {
+		literal, length, err := grabNumericLiteral(data)
+		if err != nil {
+			return length, nil, err
 		}
+		return length, &Token{Type: NumericLiteral, Value: literal}, nil
+	}
 
-		if ok, nLiteral := getNullLiteral(data[current-1:]); ok {
-			return current - 1 + len(nLiteral), &Token{Type: NullLiteral, Value: nullLiteral}, nil
-		}
+	if ok, bLiteral := getBooleanLiteral(data); ok {
+		return len(bLiteral), &Token{Type: BooleanLiteral, Value: bLiteral}, nil
+	}
 
-		if isKeyValueSeprator(r) {
-			return current, &Token{Type: KeyValueSeparator, Value: string(r)}, nil
-		}
+	if ok, nLiteral := getNullLiteral(data); ok {
+		return len(nLiteral), &Token{Type: NullLiteral, Value: nullLiteral}, nil
+	}
 
-		if isItemSeparator(r) {
-			return current, &Token{Type: ItemSepartor, Value: string(r)}, nil
-		}
+	if isKeyValueSeprator(r) {
+		return current, &Token{Type: KeyValueSeparator, Value: string(r)}, nil
+	}
 
-		if unicode.IsLetter(r) {
-			return current, nil, fmt.Errorf("Unexpected token %s", string(r))
-		}
+	if isItemSeparator(r) {
+		return current, &Token{Type: ItemSepartor, Value: string(r)}, nil
 	}
 
-	return current, nil, nil
+	return 0, nil, fmt.Errorf("Unexpected character %q.", r)
 }
 
+// grabStringLiteral decodes a string literal, from after its opening quote
+// up to and including its closing quote. On error, it returns where the error
+// is.
 func grabStringLiteral(data []byte) (string, int, error) {
 	current := 0
-	var literal []rune
+	var literal strings.Builder
 	for current < len(data) {
 		// iterate until we find the closing quote
 		r, width := utf8.DecodeRune(data[current:])
+		if r == utf8.RuneError && width == 1 {
+			return "", current, fmt.Errorf("Invalid UTF-8 byte %#x in string literal.", data[current])
+		}
 		current += width
 
-		if unicode.IsControl(r) {
-			return "", current, fmt.Errorf("Control character '%s' not allowed.", string(r))
+		if r < 0x20 {
+			return "", current - width, fmt.Errorf("Control character %U not allowed. It must be escaped.", r)
 		}
 
 		if isQuote(r) { // if closing quote
-			return string(literal), current, nil
+			return literal.String(), current, nil
+		}
+
+		if isEscapeSequence(r) {
+			decoded, escapeWidth, err := grabEscapeSequence(data[current:])
+			if err != nil {
+				// Point at the backslash.
+				return "", current - width, err
+			}
+			current += escapeWidth
+			r = decoded
 		}
 
-		literal = append(literal, r) // build the string until we find closing quote
+		literal.WriteRune(r) // build the string until we find closing quote
 	}
 	return "", current, errors.New(`Invalid string literal. Expecting '"'`)
 }
 
-func grabNumericLiteral(data []byte) (string, int, error) {
-	current := 0
-	var literal []rune
-	for current < len(data) {
-		// iterate until we find the closing quote
-		r, width := utf8.DecodeRune(data[current:])
-
-		if !unicode.IsDigit(r) && r != '.' { // Let's stop here
-			return string(literal), current - 1, nil
+// grabEscapeSequence decodes the escape sequence after a backslash, with a
+// \u escape of a high surrogate taking the \u escape of the low surrogate
+// after it.
+func grabEscapeSequence(data []byte) (rune, int, error) {
+	if len(data) == 0 {
+		return 0, 0, errors.New(`Invalid string literal. Expecting '"'`)
+	}
+	switch data[0] {
+	case '"', '\\', '/':
+		return rune(data[0]), 1, nil
+	case 'b':
+		return '\b', 1, nil
+	case 'f':
+		return '\f', 1, nil
+	case 'n':
+		return '\n', 1, nil
+	case 'r':
+		return '\r', 1, nil
+	case 't':
+		return '\t', 1, nil
+	case 'u':
+		r, err := grabHexQuad(data[1:])
+		if err != nil {
+			return 0, 1, err
 		}
-
-		current += width
-		literal = append(literal, r) // build the string until we find closing quote
-
-		if unicode.IsSpace(r) {
-			// 💣 boom !!! that's a space.
-			return "", current, errors.New(fmt.Sprintf("Unexpected %s in literal", string(r)))
+		if utf16.IsSurrogate(r) {
+			if r >= 0xdc00 {
+				return 0, 5, fmt.Errorf("Lone low surrogate \\u%04x in string literal.", r)
+			}
+			if len(data) < 7 || data[5] != '\\' || data[6] != 'u' {
+				return 0, 5, fmt.Errorf("High surrogate \\u%04x is not followed by a low surrogate.", r)
+			}
+			low, err := grabHexQuad(data[7:])
+			if err != nil {
+				return 0, 7, err
+			}
+			pair := utf16.DecodeRune(r, low)
+			if pair == unicode.ReplacementChar {
+				return 0, 11, fmt.Errorf("High surrogate \\u%04x is not followed by a low surrogate.", r)
+			}
+			return pair, 11, nil
 		}
+		return r, 5, nil
 	}
-	return "", current, errors.New(`Invalid string literal. Expecting '"'`)
+	r, _ := utf8.DecodeRune(data)
+	return 0, 1, fmt.Errorf("Invalid escape sequence '\\%s' in string literal.", string(r))
 }
 
-func linkedList(tokens []Token) *list.List {
-	l := list.New()
-	for _, token := range tokens {
-		l.PushBack(token)
+// grabHexQuad decodes the four hexadecimal digits of a \u escape.
+func grabHexQuad(data []byte) (rune, error) {
+	if len(data) < 4 {
+		return 0, errors.New("Invalid \\u escape. Expecting four hexadecimal digits.")
+	}
+	var r rune
+	for _, b := range data[:4] {
+		var digit byte
+		switch {
+		case b >= '0' && b <= '9':
+			digit = b - '0'
+		case b >= 'a' && b <= 'f':
+			digit = b - 'a' + 10
+		case b >= 'A' && b <= 'F':
+			digit = b - 'A' + 10
+		default:
+			return 0, fmt.Errorf("Invalid \\u escape %q. Expecting four hexadecimal digits.", data[:4])
+		}
+		r = r<<4 | rune(digit)
 	}
-	return l
+	return r, nil
 }
 
-func checkGrammar(tokens []Token) error {
-	linkedTokens := linkedList(tokens)
-	context := NewStack()
+// grabNumericLiteral reads a number, following the grammar of RFC 8259:
+//
+//	number = [ minus ] int [ frac ] [ exp ]
+//	int    = zero / ( digit1-9 *DIGIT )
+//	frac   = decimal-point 1*DIGIT
+//	exp    = e [ minus / plus ] 1*DIGIT
+func grabNumericLiteral(data []byte) (string, int, error) {
+	current := 0
+	if current < len(data) && data[current] == '-' {
+		current++
+	}
+
+	switch {
+	case current < len(data) && data[current] == '0':
+		current++
+		if current < len(data) && isDigit(rune(data[current])) {
+			return "", current, fmt.Errorf("Numeric literal cannot begin with 0.")
+		}
+	case current < len(data) && isDigit(rune(data[current])):
+		current = skipDigits(data, current)
+	default:
+		return "", current, invalidNumber(data, current, "a digit")
+	}
 
-	for node := linkedTokens.Front(); node != nil; node = node.Next() {
-		currentToken := node.Value.(Token)
-		var (
-			nextToken Token
-			prevToken Token
-		)
+	if current < len(data) && data[current] == '.' {
+		current++
+		digits := skipDigits(data, current)
+		if digits == current {
+			return "", current, invalidNumber(data, current, "a digit after '.'")
+		}
+		current = digits
+	}
 
-		if next := node.Next(); next != nil {
-			nextToken = next.Value.(Token)
+	if current < len(data) && (data[current] == 'e' || data[current] == 'E') {
+		current++
+		if current < len(data) && (data[current] == '+' || data[current] == '-') {
+			current++
 		}
-		if prev := node.Prev(); prev != nil {
-			prevToken = prev.Value.(Token)
+		digits := skipDigits(data, current)
+		if digits == current {
+			return "", current, invalidNumber(data, current, "a digit in the exponent")
 		}
+		current = digits
+	}
 
-		switch currentToken.Type {
-		case ObjectOpener:
-			if nextToken.Type == 0 {
-				return fmt.Errorf("Expected end of file.")
-			}
-			context.Push(ObjectContext)
-			if node.Next() == nil {
-				return fmt.Errorf("Invalid object expression.")
-			}
-			if nextToken.Type != ObjectCloser && nextToken.Type != StringLiteral {
-				return fmt.Errorf("Unexpected token %s", nextToken.Value)
-			}
-		case ArrayOpener:
-			if nextToken.Type == 0 {
-				return fmt.Errorf("Expected end of file.")
-			}
-			context.Push(ArrayContext)
-			if nextToken.Type != ArrayCloser &&
-				!isValidValue(nextToken) {
-				return fmt.Errorf("Invalid array.")
-			}
-		case KeyValueSeparator:
-			if nextToken.Type == 0 {
-				return fmt.Errorf("Expected end of file.")
-			}
-			if node.Prev() == nil || node.Next() == nil {
-				return fmt.Errorf("Missing key or value in key value pair.")
-			}
-			if prevToken.Type != StringLiteral {
-				return fmt.Errorf("Unexpected token. Expecting key for the key value pair.")
-			}
-			if !isValidValue(nextToken) {
-				return fmt.Errorf("Unexpected token. Expecting value for the key value pair.")
-			}
-		case StringLiteral:
-			currentContext := context.Peek()
-			switch currentContext {
-			case ObjectContext:
-				if nextToken.Type != KeyValueSeparator && prevToken.Type != KeyValueSeparator {
-					return fmt.Errorf("Invalid string literal in object expression.")
-				}
-			case ArrayContext:
-				if prevToken.Type != ItemSepartor &&
-					nextToken.Type != ItemSepartor &&
-					nextToken.Type != ArrayCloser {
-					return fmt.Errorf("Orphan string literal in array")
-				}
-			default:
-				return fmt.Errorf("Orphan string literal")
-			}
-		case NumericLiteral:
-			if nextToken.Type == 0 {
-				return fmt.Errorf("Expected end of file.")
-			}
-			if strings.HasPrefix(currentToken.Value, "0") {
-				return fmt.Errorf("Numeric literal cannot begin with 0.")
-			}
-			currentContext := context.Peek()
-			switch currentContext {
-			case ObjectContext:
-				if prevToken.Type != KeyValueSeparator {
-					return fmt.Errorf("Invalid numeric literal in object expression.")
-				}
-			case ArrayContext:
-				if prevToken.Type != ItemSepartor &&
-					nextToken.Type != ItemSepartor &&
-					nextToken.Type != ArrayCloser {
-					return fmt.Errorf("Orphan numeric literal in array")
-				}
-			default:
-				return fmt.Errorf("Orphan numeric literal")
-			}
-		case ObjectCloser:
-			if prevToken.Type == ItemSepartor || prevToken.Type == KeyValueSeparator {
-				return fmt.Errorf("Unexpected end of file")
-			}
-			currentContext := context.Peek()
-			if currentContext == ObjectContext {
-				context.Pop()
-			} else {
-				return fmt.Errorf("Unexpected token %s.", currentToken.Value)
-			}
-		case ArrayCloser:
-			if prevToken.Type == ItemSepartor || prevToken.Type == KeyValueSeparator {
-				return fmt.Errorf("Unexpected end of file")
-			}
-			currentContext := context.Peek()
-			if currentContext == ArrayContext {
-				context.Pop()
-			} else {
-				return fmt.Errorf("Unexpected token %s.", currentToken.Value)
-			}
+	// Anything that could carry on the number, as in 1.2.3 or 0x14, makes
+	// it malformed rather than starting the next token.
+	if current < len(data) && isNumberTail(data[current]) {
+		return "", current, invalidNumber(data, current, "the end of the number")
+	}
+	return string(data[:current]), current, nil
+}
 
-		case ItemSepartor:
-			if nextToken.Type == 0 {
-				return fmt.Errorf("Expected end of file.")
-			}
-			currentContext := context.Peek()
-			if currentContext == ObjectContext {
-				if nextToken.Type != StringLiteral {
-					return fmt.Errorf("Not expecting ',' here")
-				}
-			}
-		}
+func skipDigits(data []byte, current int) int {
+	for current < len(data) && isDigit(rune(data[current])) {
+		current++
 	}
+	return current
+}
+
+func isNumberTail(b byte) bool {
+	return b == '.' || b == '+' || b == '-' || isDigit(rune(b)) ||
+		b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// invalidNumber reports the number read so far with the byte at current,
+// which is not what was expected.
+func invalidNumber(data []byte, current int, expected string) error {
+	if current < len(data) {
+		r, _ := utf8.DecodeRune(data[current:])
+		return fmt.Errorf("Invalid numeric literal %s%s. Expecting %s.", data[:current], string(r), expected)
+	}
+	return fmt.Errorf("Invalid numeric literal %s. Expecting %s.", data[:current], expected)
+}
 
-	return nil
+// isWhitespace reports whether r is one of the four whitespace characters
+// allowed between tokens.
+func isWhitespace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
 }
 
-func isValidValue(nextToken Token) bool {
-	return nextToken.Type == StringLiteral ||
-		nextToken.Type == NumericLiteral ||
-		nextToken.Type == BooleanLiteral ||
-		nextToken.Type == ObjectOpener ||
-		nextToken.Type == ArrayOpener ||
-		nextToken.Type == NullLiteral
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
 }
 
 func isQuote(r rune) bool {
@@ -377,14 +407,14 @@ func getBooleanLiteral(data []byte) (bool, string) {
 		return false, ""
 	}
 
-	if len(data) > 4 {
+	if len(data) >= len(falseLiteral) {
 		literal := string(data[:len(falseLiteral)])
 		if literal == falseLiteral {
 			return true, literal
 		}
 	}
 
-	if len(data) > 3 {
+	if len(data) >= len(trueLiteral) {
 		literal := string(data[:len(trueLiteral)])
 		if literal == trueLiteral {
 			return true, literal
@@ -395,13 +425,13 @@ func getBooleanLiteral(data []byte) (bool, string) {
 }
 
 func getNullLiteral(data []byte) (bool, string) {
-	if data[0] != 'n' {
+	if data[0] != 'n' || len(data) < len(nullLiteral) {
 		return false, ""
 	}
 
-	literal := data[:4]
-	if string(literal) == "null" {
-		return true, "null"
+	literal := data[:len(nullLiteral)]
+	if string(literal) == nullLiteral {
+		return true, nullLiteral
 	}
 	return false, ""
 }
@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Kind int
+
+const (
+	NullKind   Kind = 1
+	BoolKind   Kind = 2
+	NumberKind Kind = 3
+	StringKind Kind = 4
+	ArrayKind  Kind = 5
+	ObjectKind Kind = 6
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NullKind:
+		return "null"
+	case BoolKind:
+		return "bool"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return "array"
+	case ObjectKind:
+		return "object"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Value is a node of a parsed JSON document: Null, Bool, Number, String,
+// Array or Object.
+type Value interface {
+	Kind() Kind
+}
+
+type Null struct{}
+
+type Bool bool
+
+// Number keeps the text of a number as written in the document.
+type Number string
+
+type String string
+
+type Array []Value
+
+// Object keeps its members in document order. Keys are not required to be
+// unique; lookups find the last member with a key, as encoding/json does.
+type Object struct {
+	Members []Member
+}
+
+type Member struct {
+	Key   string
+	Value Value
+}
+
+func (Null) Kind() Kind   { return NullKind }
+func (Bool) Kind() Kind   { return BoolKind }
+func (Number) Kind() Kind { return NumberKind }
+func (String) Kind() Kind { return StringKind }
+func (Array) Kind() Kind  { return ArrayKind }
+func (Object) Kind() Kind { return ObjectKind }
+
+// maxIntegerExponent bounds the exponent of a number read as an integer,
+// since 1e1000000000 would take a gigabyte of digits.
+const maxIntegerExponent = 10000
+
+// Text returns the number as written in the document.
+func (n Number) Text() string {
+	return string(n)
+}
+
+// Float64 returns the nearest float64, or an error when the number is out
+// of its range.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Int64 returns the number when it is a whole number, like 12, 1.0 or 2e3,
+// that fits in an int64.
+func (n Number) Int64() (int64, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i, nil
+	}
+	i, err := n.BigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, fmt.Errorf("Number %s is out of the int64 range.", string(n))
+	}
+	return i.Int64(), nil
+}
+
+// BigInt returns the number when it is a whole number, however large.
+func (n Number) BigInt() (*big.Int, error) {
+	if i, ok := new(big.Int).SetString(string(n), 10); ok {
+		return i, nil
+	}
+	if exponent := strings.IndexAny(string(n), "eE"); exponent >= 0 {
+		e, err := strconv.Atoi(strings.TrimPrefix(string(n)[exponent+1:], "+"))
+		if err != nil || e > maxIntegerExponent || e < -maxIntegerExponent {
+			return nil, fmt.Errorf("Number %s is out of range.", string(n))
+		}
+	}
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return nil, fmt.Errorf("Invalid number %s.", string(n))
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("Number %s is not a whole number.", string(n))
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// BigFloat returns the number with enough precision to keep all of its
+// decimal digits, or an error when its exponent is out of the range of a
+// big.Float.
+func (n Number) BigFloat() (*big.Float, error) {
+	// A decimal digit takes less than 4 bits.
+	precision := uint(4*len(n) + 64)
+	f, _, err := big.ParseFloat(string(n), 10, precision, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid number %s: %w", string(n), err)
+	}
+	// Past the exponent range of a big.Float, numbers become infinite or
+	// zero.
+	mantissa := string(n)
+	if exponent := strings.IndexAny(mantissa, "eE"); exponent >= 0 {
+		mantissa = mantissa[:exponent]
+	}
+	if f.IsInf() || f.Sign() == 0 && strings.ContainsAny(mantissa, "123456789") {
+		return nil, fmt.Errorf("Number %s is out of range.", string(n))
+	}
+	return f, nil
+}
+
+// Get returns the value of the last member named key.
+func (o Object) Get(key string) (Value, bool) {
+	for i := len(o.Members) - 1; i >= 0; i-- {
+		if o.Members[i].Key == key {
+			return o.Members[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the member names in document order.
+func (o Object) Keys() []string {
+	keys := make([]string, len(o.Members))
+	for i, member := range o.Members {
+		keys[i] = member.Key
+	}
+	return keys
+}
+
+func (o Object) String(key string) (string, error) {
+	v, err := o.lookup(key, StringKind)
+	if err != nil {
+		return "", err
+	}
+	return string(v.(String)), nil
+}
+
+func (o Object) Number(key string) (Number, error) {
+	v, err := o.lookup(key, NumberKind)
+	if err != nil {
+		return "", err
+	}
+	return v.(Number), nil
+}
+
+func (o Object) Bool(key string) (bool, error) {
+	v, err := o.lookup(key, BoolKind)
+	if err != nil {
+		return false, err
+	}
+	return bool(v.(Bool)), nil
+}
+
+func (o Object) Object(key string) (Object, error) {
+	v, err := o.lookup(key, ObjectKind)
+	if err != nil {
+		return Object{}, err
+	}
+	return v.(Object), nil
+}
+
+func (o Object) Array(key string) (Array, error) {
+	v, err := o.lookup(key, ArrayKind)
+	if err != nil {
+		return nil, err
+	}
+	return v.(Array), nil
+}
+
+// IsNull reports whether the member named key is there and null.
+func (o Object) IsNull(key string) bool {
+	v, ok := o.Get(key)
+	return ok && v.Kind() == NullKind
+}
+
+func (o Object) lookup(key string, kind Kind) (Value, error) {
+	v, ok := o.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("Missing key %q.", key)
+	}
+	if v.Kind() != kind {
+		return nil, fmt.Errorf("Key %q is a %s, not a %s.", key, v.Kind(), kind)
+	}
+	return v, nil
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ParseValue(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected Value
+	}{
+		{
+			input:    `{}`,
+			expected: Object{},
+		},
+		{
+			input:    `[]`,
+			expected: Array{},
+		},
+		{
+			input:    ` "top" `,
+			expected: String("top"),
+		},
+		{
+			input:    `false`,
+			expected: Bool(false),
+		},
+		{
+			input: `{"b": 1, "a": [true, false, null, "x", {}], "o": {"k": 2.5}}`,
+			expected: Object{Members: []Member{
+				{Key: "b", Value: Number("1")},
+				{Key: "a", Value: Array{Bool(true), Bool(false), Null{}, String("x"), Object{}}},
+				{Key: "o", Value: Object{Members: []Member{{Key: "k", Value: Number("2.5")}}}},
+			}},
+		},
+	}
+
+	for k, testCase := range testcases {
+		actual, err := ParseValue(strings.NewReader(testCase.input))
+		if err != nil {
+			t.Error(k, testCase.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, testCase.expected) {
+			t.Error(k, testCase.input, "Expected:", testCase.expected, "Actual:", actual)
+		}
+	}
+}
+
+func Test_ParseValueInvalid(t *testing.T) {
+	for _, input := range []string{``, `{`, `[1 2]`, `{"a":1 "b":2}`, `{} {}`} {
+		if _, err := ParseValue(strings.NewReader(input)); err == nil {
+			t.Error(input, "Expecting error")
+		}
+	}
+}
+
+func Test_objectAccessors(t *testing.T) {
+	value, err := ParseValue(strings.NewReader(`{"name": "x", "name": "y", "n": 12.5, "ok": true, "o": {}, "a": [1], "z": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	object := value.(Object)
+
+	if name, err := object.String("name"); err != nil || name != "y" {
+		t.Error("Expected:", "y", "Actual:", name, err)
+	}
+	if n, err := object.Number("n"); err != nil {
+		t.Error(err)
+	} else if f, _ := n.Float64(); f != 12.5 {
+		t.Error("Expected:", 12.5, "Actual:", f)
+	}
+	if ok, err := object.Bool("ok"); err != nil || !ok {
+		t.Error("Expected:", true, "Actual:", ok, err)
+	}
+	if _, err := object.Object("o"); err != nil {
+		t.Error(err)
+	}
+	if a, err := object.Array("a"); err != nil || len(a) != 1 {
+		t.Error("Expected:", 1, "Actual:", len(a), err)
+	}
+	if !object.IsNull("z") || object.IsNull("missing") {
+		t.Error("Expecting z only to be null")
+	}
+	if _, err := object.String("n"); err == nil {
+		t.Error("Expecting error for a number read as a string")
+	}
+	if _, err := object.Bool("missing"); err == nil {
+		t.Error("Expecting error for a missing key")
+	}
+	expectedKeys := []string{"name", "name", "n", "ok", "o", "a", "z"}
+	if keys := object.Keys(); !reflect.DeepEqual(keys, expectedKeys) {
+		t.Error("Expected:", expectedKeys, "Actual:", keys)
+	}
+}
+
+func Test_numberAccessors(t *testing.T) {
+	value, err := ParseValue(strings.NewReader(`[12, -7, 2e3, 1.0, 1.5, 9223372036854775808, 123456789012345678901234567890, 0.1, 1e400]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers := value.(Array)
+
+	int64s := []struct {
+		index    int
+		expected int64
+		valid    bool
+	}{
+		{index: 0, expected: 12, valid: true},
+		{index: 1, expected: -7, valid: true},
+		{index: 2, expected: 2000, valid: true},
+		{index: 3, expected: 1, valid: true},
+		{index: 4},
+		{index: 5},
+	}
+	for _, testCase := range int64s {
+		actual, err := numbers[testCase.index].(Number).Int64()
+		if (err == nil) != testCase.valid || actual != testCase.expected {
+			t.Error(numbers[testCase.index], "Expected:", testCase.expected, testCase.valid, "Actual:", actual, err)
+		}
+	}
+
+	integer, err := numbers[6].(Number).BigInt()
+	if err != nil || integer.String() != "123456789012345678901234567890" {
+		t.Error("Expected:", "123456789012345678901234567890", "Actual:", integer, err)
+	}
+	if f, err := numbers[7].(Number).Float64(); err != nil || f != 0.1 {
+		t.Error("Expected:", 0.1, "Actual:", f, err)
+	}
+	if _, err := numbers[8].(Number).Float64(); err == nil {
+		t.Error("Expecting error for a float64 out of range")
+	}
+	bigFloat, err := numbers[8].(Number).BigFloat()
+	if err != nil || bigFloat.Text('g', 10) != "1e+400" {
+		t.Error("Expected:", "1e+400", "Actual:", bigFloat, err)
+	}
+	bigFloat, err = numbers[6].(Number).BigFloat()
+	if err != nil || bigFloat.Text('f', 0) != "123456789012345678901234567890" {
+		t.Error("Expected:", "123456789012345678901234567890", "Actual:", bigFloat, err)
+	}
+	for _, literal := range []Number{"1e1000000000", "-1e1000000000", "1e-1000000000"} {
+		if _, err := literal.BigFloat(); err == nil {
+			t.Error("Expecting error for", literal, "out of range")
+		}
+	}
+	if bigFloat, err := Number("0e1000000000").BigFloat(); err != nil || bigFloat.Sign() != 0 {
+		t.Error("Expected:", 0, "Actual:", bigFloat, err)
+	}
+	if text := numbers[3].(Number).Text(); text != "1.0" {
+		t.Error("Expected:", "1.0", "Actual:", text)
+	}
+	if _, err := Number("1e100000").BigInt(); err == nil {
+		t.Error("Expecting error for an exponent out of range")
+	}
+}
@@ -0,0 +1,26 @@
+package parser
+
+import "testing"
+
+func Test_runeWidth(t *testing.T) {
+	testcases := map[string]struct {
+		r        rune
+		expected int
+	}{
+		"Should give ascii one column":        {r: 'a', expected: 1},
+		"Should give accented one column":     {r: 'é', expected: 1},
+		"Should give han two columns":         {r: '漢', expected: 2},
+		"Should give hangul two columns":      {r: '한', expected: 2},
+		"Should give fullwidth two columns":   {r: 'Ａ', expected: 2},
+		"Should give emoji two columns":       {r: '😀', expected: 2},
+		"Should give combining marks nothing": {r: '\u0301', expected: 0},
+		"Should give format runes nothing":    {r: '\u200b', expected: 0},
+		"Should give controls nothing":        {r: '\t', expected: 0},
+	}
+
+	for k, v := range testcases {
+		if actual := runeWidth(v.r); actual != v.expected {
+			t.Error(k, "Expected:", v.expected, "Actual:", actual)
+		}
+	}
+}